@@ -0,0 +1,107 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"errors"
+)
+
+var NotBipartite = errors.New("graph: graph is not bipartite")
+
+// Bipartite returns a boolean indicating whether the graph is bipartite when traversing edges that
+// satisfy the edge filter ef, determined by attempting a breadth-first two-coloring of each connected
+// component. If the graph is bipartite, the two independent sets partA and partB are returned;
+// the first node of each component, in the order given by Nodes(), is placed in partA, so isolated
+// nodes are always in partA. If the graph is not bipartite, partA and partB are nil.
+func (g *Undirected) Bipartite(ef EdgeFilter) (ok bool, partA, partB []Node) {
+	const (
+		none = iota
+		a
+		b
+	)
+	color := make([]byte, g.NextNodeID())
+	q := &queue{}
+	for _, s := range g.compNodes {
+		if color[s.ID()] != none {
+			continue
+		}
+		color[s.ID()] = a
+		q.Enqueue(s)
+		for q.Len() > 0 {
+			u, _ := q.Dequeue()
+			if color[u.ID()] == a {
+				partA = append(partA, u)
+			} else {
+				partB = append(partB, u)
+			}
+			for _, v := range u.Neighbors(ef) {
+				switch color[v.ID()] {
+				case none:
+					color[v.ID()] = a + b - color[u.ID()]
+					q.Enqueue(v)
+				case color[u.ID()]:
+					return false, nil, nil
+				}
+			}
+		}
+	}
+
+	return true, partA, partB
+}
+
+// MaximumBipartiteMatching returns a maximum cardinality matching of the graph: a largest set of
+// edges no two of which share a node. Matches are grown from the nodes of partA, as returned by
+// Bipartite, by searching for augmenting paths. Parallel edges are treated as a single adjacency, with
+// the first such edge held by the partA node used in the matching. If the graph is not bipartite, the
+// error NotBipartite is returned.
+func (g *Undirected) MaximumBipartiteMatching() (matching []Edge, err error) {
+	all := func(_ Edge) bool { return true }
+	ok, partA, _ := g.Bipartite(all)
+	if !ok {
+		return nil, NotBipartite
+	}
+
+	// mate holds the edge matching each node, indexed by ID.
+	mate := make([]Edge, g.NextNodeID())
+	var seen []bool
+	var augment func(u Node) bool
+	augment = func(u Node) bool {
+		for _, e := range u.Edges() {
+			v := adjacent(e, u)
+			if marked(v, seen) {
+				continue
+			}
+			seen = mark(v, seen)
+			if m := mate[v.ID()]; m == nil || augment(adjacent(m, v)) {
+				mate[u.ID()], mate[v.ID()] = e, e
+				return true
+			}
+		}
+		return false
+	}
+	for _, u := range partA {
+		seen = seen[:0]
+		augment(u)
+	}
+
+	for _, u := range partA {
+		if e := mate[u.ID()]; e != nil {
+			matching = append(matching, e)
+		}
+	}
+
+	return matching, nil
+}
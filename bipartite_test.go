@@ -0,0 +1,66 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+)
+
+// Tests
+func (s *S) TestBipartite(c *check.C) {
+	odd := undirected(c, []e{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0}})
+	ok, a, b := odd.Bipartite(all)
+	c.Check(ok, check.Equals, false)
+	c.Check(a, check.IsNil)
+	c.Check(b, check.IsNil)
+
+	even := undirected(c, []e{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 5}, {5, 0}, {6, 7}})
+	even.AddID(8)
+	ok, a, b = even.Bipartite(all)
+	c.Check(ok, check.Equals, true)
+	c.Check(len(a)+len(b), check.Equals, even.Order())
+	side := make(map[Node]int)
+	for _, n := range a {
+		side[n] = 1
+	}
+	for _, n := range b {
+		side[n] = 2
+	}
+	for _, e := range even.Edges() {
+		c.Check(side[e.Head()] != side[e.Tail()], check.Equals, true)
+	}
+	c.Check(side[even.Node(8)], check.Equals, 1)
+
+	ok, _, _ = undirected(c, []e{{0, 1}, {1, 1}}).Bipartite(all)
+	c.Check(ok, check.Equals, false)
+}
+
+func (s *S) TestMaximumBipartiteMatching(c *check.C) {
+	g := weightedUndirected(c, []we{{0, 4, 1}, {0, 5, 1}, {1, 4, 1}, {1, 4, 2}, {2, 5, 1}, {2, 6, 1}, {3, 6, 1}, {7, 8, 1}})
+	m, err := g.MaximumBipartiteMatching()
+	c.Assert(err, check.IsNil)
+	c.Check(m, check.HasLen, 4)
+	used := make(map[Node]bool)
+	for _, e := range m {
+		u, v := e.Nodes()
+		c.Check(used[u] || used[v], check.Equals, false)
+		used[u], used[v] = true, true
+	}
+
+	g.ConnectByID(4, 5, 1, 0)
+	_, err = g.MaximumBipartiteMatching()
+	c.Check(err, check.Equals, NotBipartite)
+}
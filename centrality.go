@@ -0,0 +1,312 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"math"
+)
+
+// BetweennessCentrality returns the betweenness centrality of each node in the graph, keyed by node ID,
+// computed using Brandes' algorithm with each edge that satisfies the edge filter ef counted as a
+// single hop. Parallel edges are counted as distinct paths. The values returned are not normalized;
+// see NormalizeBetweenness.
+func (g *Undirected) BetweennessCentrality(ef EdgeFilter) map[int]float64 {
+	return g.betweenness(ef, false)
+}
+
+// WeightedBetweennessCentrality returns the betweenness centrality of each node in the graph, keyed by
+// node ID, computed using Brandes' algorithm with edge weights of edges that satisfy the edge filter ef
+// used as distances. Edge weights must be positive. The values returned are not normalized; see
+// NormalizeBetweenness.
+func (g *Undirected) WeightedBetweennessCentrality(ef EdgeFilter) map[int]float64 {
+	return g.betweenness(ef, true)
+}
+
+// EdgeBetweenness returns the betweenness of each edge in the graph, keyed by edge ID, the number of
+// shortest paths between pairs of nodes that pass through the edge, with paths of equal length sharing
+// each pair's contribution. Betweenness is computed using Brandes' algorithm with each edge that
+// satisfies the edge filter ef counted as a single hop. Repeatedly removing the edge of highest
+// betweenness is the basis of the Girvan-Newman community detection method.
+func (g *Undirected) EdgeBetweenness(ef EdgeFilter) map[int]float64 {
+	return g.edgeBetweenness(ef, false)
+}
+
+// WeightedEdgeBetweenness returns the betweenness of each edge in the graph, keyed by edge ID, as
+// EdgeBetweenness does, but with edge weights of edges that satisfy the edge filter ef used as
+// distances. Edge weights must be positive.
+func (g *Undirected) WeightedEdgeBetweenness(ef EdgeFilter) map[int]float64 {
+	return g.edgeBetweenness(ef, true)
+}
+
+// NormalizeBetweenness scales the betweenness centrality values in b, as returned by
+// BetweennessCentrality or WeightedBetweennessCentrality for the graph, by the number of pairs of
+// nodes not including the node being scored, 2/((n-1)(n-2)), so that values lie in [0, 1] and can be
+// compared between graphs of different orders. Graphs with fewer than three nodes are left unaltered.
+func (g *Undirected) NormalizeBetweenness(b map[int]float64) {
+	n := float64(g.Order())
+	if n < 3 {
+		return
+	}
+	f := 2 / ((n - 1) * (n - 2))
+	for id := range b {
+		b[id] *= f
+	}
+}
+
+// ClosenessCentrality returns the closeness centrality of each node in the graph, keyed by node ID,
+// using edge weights of edges that satisfy the edge filter ef as distances. The closeness of a node is
+// the reciprocal of the sum of its shortest path distances to the nodes it can reach. So that nodes in
+// disconnected graphs can be compared, the Wasserman-Faust correction is applied, scaling the value by
+// the fraction of the other nodes in the graph that can be reached; in a connected graph this yields
+// the usual definition multiplied by n-1. Nodes that cannot reach any other node have a closeness of
+// zero. If a negative edge weight is encountered, nil is returned.
+func (g *Undirected) ClosenessCentrality(ef EdgeFilter) map[int]float64 {
+	cc := make(map[int]float64, g.Order())
+	n := float64(g.Order())
+	for _, u := range g.compNodes {
+		dist, _, err := g.ShortestPaths(u, ef)
+		if err != nil {
+			return nil
+		}
+		r := float64(len(dist) - 1)
+		if r == 0 {
+			cc[u.ID()] = 0
+			continue
+		}
+		var sum float64
+		for _, d := range dist {
+			sum += d
+		}
+		cc[u.ID()] = (r / (n - 1)) * (r / sum)
+	}
+
+	return cc
+}
+
+// PageRank returns the PageRank of each node in the graph, keyed by node ID, with each out-edge of
+// a node equally likely to be followed. The random surfer follows an out-edge with probability damping
+// and otherwise jumps to a node chosen uniformly. The rank of nodes without out-edges is redistributed
+// uniformly over all nodes. Iteration stops when the L1 norm of the change in ranks falls below tol or
+// after maxIter iterations.
+func (g *Directed) PageRank(damping, tol float64, maxIter int) map[int]float64 {
+	return g.pageRank(damping, tol, maxIter, func(_ Edge) float64 { return 1 })
+}
+
+// WeightedPageRank returns the PageRank of each node in the graph, keyed by node ID, in the same
+// way as PageRank, except that out-edges are followed with probability proportional to their weight.
+// Nodes whose out-edges have a total weight of zero are treated as having no out-edges.
+func (g *Directed) WeightedPageRank(damping, tol float64, maxIter int) map[int]float64 {
+	return g.pageRank(damping, tol, maxIter, func(e Edge) float64 { return e.Weight() })
+}
+
+func (g *Directed) pageRank(damping, tol float64, maxIter int, weight func(Edge) float64) map[int]float64 {
+	n := float64(g.Order())
+	if n == 0 {
+		return map[int]float64{}
+	}
+
+	out := make([]float64, g.NextNodeID())
+	for _, e := range g.compEdges {
+		out[e.Tail().ID()] += weight(e)
+	}
+
+	rank := make([]float64, g.NextNodeID())
+	next := make([]float64, g.NextNodeID())
+	for _, u := range g.compNodes {
+		rank[u.ID()] = 1 / n
+	}
+	for i := 0; i < maxIter; i++ {
+		var dangling float64
+		for _, u := range g.compNodes {
+			next[u.ID()] = 0
+			if out[u.ID()] == 0 {
+				dangling += rank[u.ID()]
+			}
+		}
+		for _, e := range g.compEdges {
+			u := e.Tail().ID()
+			if out[u] != 0 {
+				next[e.Head().ID()] += rank[u] * weight(e) / out[u]
+			}
+		}
+		var delta float64
+		for _, u := range g.compNodes {
+			id := u.ID()
+			next[id] = (1-damping)/n + damping*(next[id]+dangling/n)
+			delta += math.Abs(next[id] - rank[id])
+		}
+		rank, next = next, rank
+		if delta < tol {
+			break
+		}
+	}
+
+	pr := make(map[int]float64, g.Order())
+	for _, u := range g.compNodes {
+		pr[u.ID()] = rank[u.ID()]
+	}
+
+	return pr
+}
+
+func (g *Undirected) betweenness(ef EdgeFilter, weighted bool) map[int]float64 {
+	cb := make(map[int]float64, g.Order())
+	for _, n := range g.compNodes {
+		cb[n.ID()] = 0
+	}
+
+	b := newBrandes(g.NextNodeID())
+	delta := make([]float64, g.NextNodeID())
+	for _, s := range g.compNodes {
+		if weighted {
+			b.dijkstra(s, ef)
+		} else {
+			b.bfs(s, ef)
+		}
+		for i := range delta {
+			delta[i] = 0
+		}
+		for i := len(b.order) - 1; i >= 0; i-- {
+			w := b.order[i].ID()
+			for _, p := range b.pred[w] {
+				v := p.Node.ID()
+				delta[v] += b.sigma[v] / b.sigma[w] * (1 + delta[w])
+			}
+			if b.order[i] != s {
+				cb[w] += delta[w]
+			}
+		}
+	}
+
+	// Each path has been counted from both of its ends.
+	for id := range cb {
+		cb[id] /= 2
+	}
+
+	return cb
+}
+
+func (g *Undirected) edgeBetweenness(ef EdgeFilter, weighted bool) map[int]float64 {
+	cb := make(map[int]float64, g.Size())
+	for _, e := range g.compEdges {
+		cb[e.ID()] = 0
+	}
+
+	b := newBrandes(g.NextNodeID())
+	delta := make([]float64, g.NextNodeID())
+	for _, s := range g.compNodes {
+		if weighted {
+			b.dijkstra(s, ef)
+		} else {
+			b.bfs(s, ef)
+		}
+		for i := range delta {
+			delta[i] = 0
+		}
+		for i := len(b.order) - 1; i >= 0; i-- {
+			w := b.order[i].ID()
+			for _, p := range b.pred[w] {
+				v := p.Node.ID()
+				d := b.sigma[v] / b.sigma[w] * (1 + delta[w])
+				cb[p.Edge.ID()] += d
+				delta[v] += d
+			}
+		}
+	}
+
+	// Each path has been counted from both of its ends.
+	for id := range cb {
+		cb[id] /= 2
+	}
+
+	return cb
+}
+
+// brandes holds the single source shortest path state used by Brandes' algorithm.
+type brandes struct {
+	order []Node    // Nodes in order of non-decreasing distance from the source.
+	pred  [][]Hop   // Predecessor node and connecting edge on shortest paths, by node ID.
+	sigma []float64 // Number of shortest paths from the source, by node ID.
+	dist  []float64 // Distance from the source, by node ID.
+}
+
+func newBrandes(n int) *brandes {
+	return &brandes{
+		pred:  make([][]Hop, n),
+		sigma: make([]float64, n),
+		dist:  make([]float64, n),
+	}
+}
+
+func (b *brandes) reset(s Node) {
+	b.order = b.order[:0]
+	for i := range b.pred {
+		b.pred[i] = b.pred[i][:0]
+		b.sigma[i] = 0
+		b.dist[i] = math.Inf(1)
+	}
+	b.sigma[s.ID()] = 1
+	b.dist[s.ID()] = 0
+}
+
+func (b *brandes) bfs(s Node, ef EdgeFilter) {
+	b.reset(s)
+	q := &queue{}
+	q.Enqueue(s)
+	for q.Len() > 0 {
+		v, _ := q.Dequeue()
+		b.order = append(b.order, v)
+		for _, h := range v.Hops(ef) {
+			w := h.Node.ID()
+			if math.IsInf(b.dist[w], 1) {
+				b.dist[w] = b.dist[v.ID()] + 1
+				q.Enqueue(h.Node)
+			}
+			if b.dist[w] == b.dist[v.ID()]+1 {
+				b.sigma[w] += b.sigma[v.ID()]
+				b.pred[w] = append(b.pred[w], Hop{Edge: h.Edge, Node: v})
+			}
+		}
+	}
+}
+
+func (b *brandes) dijkstra(s Node, ef EdgeFilter) {
+	b.reset(s)
+	var done []bool
+	pq := &PriorityQueue{}
+	pq.Push(s, 0)
+	for pq.Len() > 0 {
+		v, d := pq.Pop()
+		done = mark(v, done)
+		b.order = append(b.order, v)
+		for _, h := range v.Hops(ef) {
+			if marked(h.Node, done) {
+				continue
+			}
+			w := h.Node.ID()
+			switch nd := d + h.Edge.Weight(); {
+			case nd < b.dist[w]:
+				b.dist[w] = nd
+				b.sigma[w] = b.sigma[v.ID()]
+				b.pred[w] = append(b.pred[w][:0], Hop{Edge: h.Edge, Node: v})
+				pq.Push(h.Node, nd)
+			case nd == b.dist[w]:
+				b.sigma[w] += b.sigma[v.ID()]
+				b.pred[w] = append(b.pred[w], Hop{Edge: h.Edge, Node: v})
+			}
+		}
+	}
+}
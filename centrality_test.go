@@ -0,0 +1,134 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+)
+
+// Helpers
+func star(c *check.C, leaves int) *Undirected {
+	var edges []e
+	for i := 1; i <= leaves; i++ {
+		edges = append(edges, e{0, i})
+	}
+	return undirected(c, edges)
+}
+
+// Tests
+func (s *S) TestBetweennessCentrality(c *check.C) {
+	g := star(c, 5)
+	for _, b := range []map[int]float64{g.BetweennessCentrality(all), g.WeightedBetweennessCentrality(all)} {
+		c.Check(len(b), check.Equals, g.Order())
+		c.Check(b[0], check.Equals, 10.)
+		for i := 1; i <= 5; i++ {
+			c.Check(b[i], check.Equals, 0.)
+		}
+		g.NormalizeBetweenness(b)
+		c.Check(b[0], check.Equals, 1.)
+	}
+
+	p := path(c, 5)
+	b := p.BetweennessCentrality(all)
+	c.Check(b, check.DeepEquals, map[int]float64{0: 0, 1: 3, 2: 4, 3: 3, 4: 0})
+
+	// Two routes of equal length from 0 to 3 share the pair's dependency.
+	d := undirected(c, []e{{0, 1}, {0, 2}, {1, 3}, {2, 3}})
+	c.Check(d.BetweennessCentrality(all), check.DeepEquals, map[int]float64{0: 0.5, 1: 0.5, 2: 0.5, 3: 0.5})
+
+	w := weightedUndirected(c, []we{{0, 1, 1}, {1, 2, 1}, {0, 2, 3}})
+	c.Check(w.BetweennessCentrality(all)[1], check.Equals, 0.)
+	c.Check(w.WeightedBetweennessCentrality(all)[1], check.Equals, 1.)
+}
+
+func (s *S) TestEdgeBetweenness(c *check.C) {
+	// Two complete graphs on four nodes joined by a bridge from 3 to 4.
+	var barbell []e
+	for _, off := range []int{0, 4} {
+		for i := 0; i < 4; i++ {
+			for j := i + 1; j < 4; j++ {
+				barbell = append(barbell, e{i + off, j + off})
+			}
+		}
+	}
+	g := undirected(c, append(barbell, e{3, 4}))
+	ce, _ := g.ConnectingEdges(g.Node(3), g.Node(4))
+	c.Assert(ce, check.HasLen, 1)
+	bridge := ce[0].ID()
+	for _, b := range []map[int]float64{g.EdgeBetweenness(all), g.WeightedEdgeBetweenness(all)} {
+		c.Check(b, check.HasLen, g.Size())
+		c.Check(b[bridge], check.Equals, 16.)
+		for id, v := range b {
+			if id != bridge {
+				c.Check(v < b[bridge], check.Equals, true, check.Commentf("edge %d", id))
+			}
+		}
+	}
+
+	p := path(c, 4)
+	c.Check(p.EdgeBetweenness(all), check.DeepEquals, map[int]float64{0: 3, 1: 4, 2: 3})
+
+	d := undirected(c, []e{{0, 1}, {0, 2}, {1, 3}, {2, 3}})
+	c.Check(d.EdgeBetweenness(all), check.DeepEquals, map[int]float64{0: 2, 1: 2, 2: 2, 3: 2})
+
+	w := weightedUndirected(c, []we{{0, 1, 1}, {1, 2, 1}, {0, 2, 3}})
+	c.Check(w.EdgeBetweenness(all), check.DeepEquals, map[int]float64{0: 1, 1: 1, 2: 1})
+	c.Check(w.WeightedEdgeBetweenness(all), check.DeepEquals, map[int]float64{0: 2, 1: 2, 2: 0})
+}
+
+func (s *S) TestClosenessCentrality(c *check.C) {
+	g := path(c, 5)
+	cc := g.ClosenessCentrality(all)
+	c.Check(cc[0], check.Equals, 4./10)
+	c.Check(cc[1], check.Equals, 4./7)
+	c.Check(cc[2], check.Equals, 4./6)
+	c.Check(cc[0], check.Equals, cc[4])
+	c.Check(cc[2] > cc[1] && cc[1] > cc[0], check.Equals, true)
+
+	// Two disjoint copies of a three node path.
+	g = undirected(c, []e{{0, 1}, {1, 2}, {3, 4}, {4, 5}})
+	g.AddID(6)
+	cc = g.ClosenessCentrality(all)
+	c.Check(cc[1], check.Equals, (2./6)*(2./2))
+	c.Check(cc[0], check.Equals, (2./6)*(2./3))
+	c.Check(cc[6], check.Equals, 0.)
+}
+
+func (s *S) TestPageRank(c *check.C) {
+	g := directed(c, []e{{0, 1}, {0, 2}, {1, 2}, {2, 0}, {3, 2}})
+	g.AddID(4)
+	pr := g.PageRank(0.85, 1e-10, 1000)
+	c.Check(len(pr), check.Equals, 5)
+	var sum float64
+	for _, r := range pr {
+		sum += r
+	}
+	c.Check(math.Abs(sum-1) < 1e-9, check.Equals, true)
+	c.Check(pr[2] > pr[0], check.Equals, true)
+	c.Check(pr[0] > pr[1], check.Equals, true)
+	c.Check(pr[1] > pr[3], check.Equals, true)
+	c.Check(pr[3], check.Equals, pr[4])
+
+	for _, e := range g.Edges() {
+		if e.Tail().ID() == 0 && e.Head().ID() == 1 {
+			e.SetWeight(9)
+		}
+	}
+	wpr := g.WeightedPageRank(0.85, 1e-10, 1000)
+	c.Check(wpr[1] > pr[1], check.Equals, true)
+	c.Check(g.PageRank(0.85, 1e-10, 1000), check.DeepEquals, pr)
+}
@@ -0,0 +1,298 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// GirvanNewman returns the communities of the graph found by the Girvan-Newman method. Starting from
+// a clone of the graph, the edge with the highest edge betweenness, taking the lowest edge ID among
+// ties, is repeatedly removed until the graph is split into at least targetComponents connected
+// components, or no edges remain. Edge betweenness is recomputed after each removal. The communities
+// are returned as for ConnectedComponents, holding the nodes of the graph. The graph is not altered.
+func (g *Undirected) GirvanNewman(targetComponents int) [][]Node {
+	all := func(_ Edge) bool { return true }
+	h := g.Clone()
+	cc := h.ConnectedComponents(all)
+	for len(cc) < targetComponents && h.Size() > 0 {
+		eb := h.EdgeBetweenness(all)
+		var max Edge
+		for _, e := range h.edges {
+			if e != nil && (max == nil || eb[e.ID()] > eb[max.ID()]) {
+				max = e
+			}
+		}
+		u, v := max.Nodes()
+		h.DeleteEdge(max)
+		if u == v {
+			// Removing a self-loop cannot split a component.
+			continue
+		}
+		cc = h.ConnectedComponents(all)
+	}
+
+	communities := make([][]Node, len(cc))
+	for i, c := range cc {
+		communities[i] = make([]Node, len(c))
+		for j, n := range c {
+			communities[i][j] = g.nodes[n.ID()]
+		}
+	}
+
+	return communities
+}
+
+// Louvain returns the communities of the graph found by the Louvain method, and the modularity of
+// the partition at the given resolution. Edge weights are used as the strength of connections and
+// must not be negative. Each node is initially placed in its own community. Nodes are then visited in
+// a random order, taken from src, and each is moved to the neighboring community giving the greatest
+// gain in modularity until no move improves it, after which the communities are collapsed into single
+// nodes of a new graph and the process is repeated until no node moves. Higher resolutions favour
+// smaller communities. Communities are ordered by their first node in Nodes, and the nodes of each
+// community are in the order given by Nodes. If the graph has no edge weight, each node is returned
+// in its own community with a modularity of zero.
+func (g *Undirected) Louvain(resolution float64, src rand.Source) ([][]Node, float64) {
+	rnd := rand.New(src)
+
+	lg := newLouvainGraph(g)
+	member := make([]int, len(g.compNodes))
+	for i := range member {
+		member[i] = i
+	}
+	if lg.m2 > 0 {
+		for level := lg; ; {
+			comm, moved := level.localMoves(resolution, rnd)
+			if !moved {
+				break
+			}
+			for i, c := range member {
+				member[i] = comm[c]
+			}
+			level = level.aggregate(comm)
+		}
+	}
+
+	var communities [][]Node
+	index := make(map[int]int)
+	for i, n := range g.compNodes {
+		j, ok := index[member[i]]
+		if !ok {
+			j = len(communities)
+			index[member[i]] = j
+			communities = append(communities, nil)
+		}
+		communities[j] = append(communities[j], n)
+		member[i] = j
+	}
+
+	return communities, lg.modularity(member, len(communities), resolution)
+}
+
+// Modularity returns Newman's modularity of the partition of the nodes of the graph into communities,
+// using edge weights as the strength of connections. Every node of the graph must appear in exactly
+// one community; if a node is repeated, is missing from all communities or is not in the graph, an
+// error is returned. If the graph has no edge weight, the modularity is zero.
+func (g *Undirected) Modularity(communities [][]Node) (float64, error) {
+	member := make([]int, len(g.compNodes))
+	for i := range member {
+		member[i] = -1
+	}
+	for c, nodes := range communities {
+		for _, n := range nodes {
+			if ok, _ := g.Has(n); !ok || g.nodes[n.ID()] != n {
+				return 0, fmt.Errorf("graph: node %d in community %d is not in the graph", n.ID(), c)
+			}
+			if d := member[n.index()]; d >= 0 {
+				return 0, fmt.Errorf("graph: node %d is in communities %d and %d", n.ID(), d, c)
+			}
+			member[n.index()] = c
+		}
+	}
+	for i, c := range member {
+		if c < 0 {
+			return 0, fmt.Errorf("graph: node %d is not in any community", g.compNodes[i].ID())
+		}
+	}
+
+	return newLouvainGraph(g).modularity(member, len(communities), 1), nil
+}
+
+// louvainGraph is the compact weighted graph representation used by Louvain.
+type louvainGraph struct {
+	adj  [][]louvainEdge // Edges to other nodes, by node index.
+	self []float64       // Self-loop weight counted at both ends, by node index.
+	k    []float64       // Total weight of edges counted at both ends, by node index.
+	m2   float64         // Total edge weight, counted at both ends.
+}
+
+type louvainEdge struct {
+	to int
+	w  float64
+}
+
+func newLouvainGraph(g *Undirected) *louvainGraph {
+	n := len(g.compNodes)
+	lg := &louvainGraph{
+		adj:  make([][]louvainEdge, n),
+		self: make([]float64, n),
+		k:    make([]float64, n),
+	}
+	for _, e := range g.compEdges {
+		u, v := e.Nodes()
+		i, j, w := u.index(), v.index(), e.Weight()
+		if i == j {
+			lg.self[i] += 2 * w
+		} else {
+			lg.adj[i] = append(lg.adj[i], louvainEdge{to: j, w: w})
+			lg.adj[j] = append(lg.adj[j], louvainEdge{to: i, w: w})
+		}
+		lg.k[i] += w
+		lg.k[j] += w
+		lg.m2 += 2 * w
+	}
+	return lg
+}
+
+// localMoves repeatedly moves each node to the neighboring community giving the greatest gain in
+// modularity until no node moves. The community of each node is returned with communities
+// numbered from zero in order of their first node, with a boolean indicating whether any node moved.
+func (lg *louvainGraph) localMoves(resolution float64, rnd *rand.Rand) (comm []int, moved bool) {
+	n := len(lg.k)
+	comm = make([]int, n)
+	tot := make([]float64, n)
+	for i := range comm {
+		comm[i] = i
+		tot[i] = lg.k[i]
+	}
+
+	wc := make([]float64, n)
+	var near []int
+	for improved := true; improved; {
+		improved = false
+		for _, i := range rnd.Perm(n) {
+			// Find the weight of edges from i to each neighboring community.
+			near = append(near[:0], comm[i])
+			for _, e := range lg.adj[i] {
+				c := comm[e.to]
+				if wc[c] == 0 && c != comm[i] {
+					near = append(near, c)
+				}
+				wc[c] += e.w
+			}
+
+			ki := lg.k[i]
+			tot[comm[i]] -= ki
+			best, bestGain := comm[i], wc[comm[i]]-resolution*tot[comm[i]]*ki/lg.m2
+			for _, c := range near[1:] {
+				if gain := wc[c] - resolution*tot[c]*ki/lg.m2; gain > bestGain {
+					best, bestGain = c, gain
+				}
+			}
+			for _, c := range near {
+				wc[c] = 0
+			}
+			tot[best] += ki
+			if best != comm[i] {
+				comm[i] = best
+				improved = true
+				moved = true
+			}
+		}
+	}
+
+	index := make(map[int]int)
+	for i, c := range comm {
+		j, ok := index[c]
+		if !ok {
+			j = len(index)
+			index[c] = j
+		}
+		comm[i] = j
+	}
+
+	return comm, moved
+}
+
+// aggregate returns a new louvainGraph with a node for each community in comm, as returned by
+// localMoves, joined by the total weight of edges between their members.
+func (lg *louvainGraph) aggregate(comm []int) *louvainGraph {
+	n := 0
+	for _, c := range comm {
+		if c >= n {
+			n = c + 1
+		}
+	}
+	ag := &louvainGraph{
+		adj:  make([][]louvainEdge, n),
+		self: make([]float64, n),
+		k:    make([]float64, n),
+		m2:   lg.m2,
+	}
+	between := make([]map[int]float64, n)
+	for i, c := range comm {
+		ag.self[c] += lg.self[i]
+		ag.k[c] += lg.k[i]
+		for _, e := range lg.adj[i] {
+			d := comm[e.to]
+			if d == c {
+				ag.self[c] += e.w
+				continue
+			}
+			if between[c] == nil {
+				between[c] = make(map[int]float64)
+			}
+			between[c][d] += e.w
+		}
+	}
+	for c, b := range between {
+		to := make([]int, 0, len(b))
+		for d := range b {
+			to = append(to, d)
+		}
+		sort.Ints(to)
+		for _, d := range to {
+			ag.adj[c] = append(ag.adj[c], louvainEdge{to: d, w: b[d]})
+		}
+	}
+	return ag
+}
+
+// modularity returns the modularity at the given resolution of the partition of the nodes of lg
+// into n communities given by comm.
+func (lg *louvainGraph) modularity(comm []int, n int, resolution float64) float64 {
+	if lg.m2 == 0 {
+		return 0
+	}
+	in := make([]float64, n)
+	tot := make([]float64, n)
+	for i, c := range comm {
+		in[c] += lg.self[i]
+		tot[c] += lg.k[i]
+		for _, e := range lg.adj[i] {
+			if comm[e.to] == c {
+				in[c] += e.w
+			}
+		}
+	}
+	var q float64
+	for c := range in {
+		q += in[c]/lg.m2 - resolution*(tot[c]/lg.m2)*(tot[c]/lg.m2)
+	}
+	return q
+}
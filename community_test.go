@@ -0,0 +1,134 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+func cliques(c *check.C, n, size int) *Undirected {
+	var edges []e
+	for k := 0; k < n; k++ {
+		off := k * size
+		for i := 0; i < size; i++ {
+			for j := i + 1; j < size; j++ {
+				edges = append(edges, e{i + off, j + off})
+			}
+		}
+		if k > 0 {
+			edges = append(edges, e{off - 1, off})
+		}
+	}
+	return undirected(c, edges)
+}
+
+func communityIDs(communities [][]Node) [][]int {
+	var ids [][]int
+	for _, c := range communities {
+		var cid []int
+		for _, n := range c {
+			cid = append(cid, n.ID())
+		}
+		sort.Ints(cid)
+		ids = append(ids, cid)
+	}
+	return ids
+}
+
+// Tests
+func (s *S) TestGirvanNewman(c *check.C) {
+	g := undirected(c, []e{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {3, 4}, {4, 5}, {5, 3}, {5, 5}})
+	size := g.Size()
+	communities := g.GirvanNewman(2)
+	c.Check(communityIDs(communities), check.DeepEquals, [][]int{{0, 1, 2}, {3, 4, 5}})
+	for _, cm := range communities {
+		for _, n := range cm {
+			c.Check(g.Node(n.ID()), check.Equals, n)
+		}
+	}
+	c.Check(g.Size(), check.Equals, size)
+	c.Check(g.IsConnected(all), check.Equals, true)
+
+	c.Check(g.GirvanNewman(1), check.HasLen, 1)
+	c.Check(g.GirvanNewman(100), check.HasLen, g.Order())
+}
+
+func (s *S) TestModularity(c *check.C) {
+	g := cliques(c, 2, 3)
+	n := g.Node
+	triangles := [][]Node{{n(0), n(1), n(2)}, {n(3), n(4), n(5)}}
+	q, err := g.Modularity(triangles)
+	c.Assert(err, check.IsNil)
+	c.Check(math.Abs(q-5./14) < 1e-12, check.Equals, true, check.Commentf("q=%v", q))
+
+	q, err = g.Modularity([][]Node{g.Nodes()})
+	c.Assert(err, check.IsNil)
+	c.Check(q, check.Equals, 0.)
+
+	communities, lq := g.Louvain(1, rand.NewSource(1))
+	q, err = g.Modularity(communities)
+	c.Assert(err, check.IsNil)
+	c.Check(q, check.Equals, lq)
+
+	// Weights change the contribution of each edge.
+	w := weightedUndirected(c, []we{{0, 1, 3}, {1, 2, 1}})
+	q, err = w.Modularity([][]Node{{w.Node(0), w.Node(1)}, {w.Node(2)}})
+	c.Assert(err, check.IsNil)
+	c.Check(math.Abs(q-(6./8-(7./8)*(7./8)-(1./8)*(1./8))) < 1e-12, check.Equals, true, check.Commentf("q=%v", q))
+
+	_, err = g.Modularity([][]Node{{n(0), n(1), n(2)}, {n(3), n(4)}})
+	c.Check(err, check.ErrorMatches, "graph: node 5 is not in any community")
+	_, err = g.Modularity([][]Node{{n(0), n(1), n(2)}, {n(2), n(3), n(4), n(5)}})
+	c.Check(err, check.ErrorMatches, "graph: node 2 is in communities 0 and 1")
+	_, err = g.Modularity([][]Node{{n(0), n(1), n(2), g.NewNode()}, {n(3), n(4), n(5)}})
+	c.Check(err, check.ErrorMatches, "graph: node 6 in community 0 is not in the graph")
+}
+
+func (s *S) TestLouvain(c *check.C) {
+	g := cliques(c, 2, 5)
+	for seed := int64(0); seed < 10; seed++ {
+		communities, q := g.Louvain(1, rand.NewSource(seed))
+		c.Check(communityIDs(communities), check.DeepEquals, [][]int{{0, 1, 2, 3, 4}, {5, 6, 7, 8, 9}})
+		// Each clique holds 10 of the 21 edges and half of the total degree.
+		c.Check(q > 0, check.Equals, true)
+		c.Check(math.Abs(q-2*(10./21-0.25)) < 1e-12, check.Equals, true, check.Commentf("q=%v", q))
+	}
+
+	communities, q := g.Louvain(0, rand.NewSource(1))
+	c.Check(communities, check.HasLen, 1)
+	c.Check(q, check.Equals, 1.)
+
+	g = cliques(c, 4, 4)
+	a, qa := g.Louvain(1, rand.NewSource(2))
+	b, qb := g.Louvain(1, rand.NewSource(2))
+	c.Check(communityIDs(a), check.DeepEquals, communityIDs(b))
+	c.Check(qa, check.Equals, qb)
+	c.Check(a, check.HasLen, 4)
+
+	communities, q = undirected(c, []e{{0, 1}, {2, 3}}).Louvain(1, rand.NewSource(1))
+	c.Check(communityIDs(communities), check.DeepEquals, [][]int{{0, 1}, {2, 3}})
+	c.Check(q, check.Equals, 0.5)
+
+	h := NewUndirected()
+	h.AddID(0)
+	h.AddID(1)
+	communities, q = h.Louvain(1, rand.NewSource(1))
+	c.Check(communities, check.HasLen, 2)
+	c.Check(q, check.Equals, 0.)
+}
@@ -0,0 +1,230 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+// HasCycle returns a boolean indicating whether the graph contains a cycle when traversing edges
+// that satisfy the edge filter ef. Self-loops and parallel edges form cycles.
+func (g *Undirected) HasCycle(ef EdgeFilter) bool {
+	return g.FindCycle(ef) != nil
+}
+
+// FindCycle returns a cycle in the graph as an ordered slice of edges, traversing edges that satisfy
+// the edge filter ef. Consecutive edges in the slice share a node, and the last edge leads back to
+// the node the first edge leaves from. A self-loop is returned as a single edge cycle. If the graph is
+// acyclic, nil is returned.
+func (g *Undirected) FindCycle(ef EdgeFilter) []Edge {
+	type frame struct {
+		n    Node
+		hops []*Hop
+		i    int
+	}
+
+	var visits []bool
+	parent := make(map[int]Edge)
+	for _, s := range g.compNodes {
+		if marked(s, visits) {
+			continue
+		}
+		visits = mark(s, visits)
+		stack := []frame{{n: s, hops: s.Hops(ef)}}
+		for len(stack) > 0 {
+			f := &stack[len(stack)-1]
+			if f.i == len(f.hops) {
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			h := f.hops[f.i]
+			f.i++
+			if h.Edge == parent[f.n.ID()] {
+				continue
+			}
+			if !marked(h.Node, visits) {
+				visits = mark(h.Node, visits)
+				parent[h.Node.ID()] = h.Edge
+				stack = append(stack, frame{n: h.Node, hops: h.Node.Hops(ef)})
+				continue
+			}
+
+			// h.Node is an ancestor of f.n on the search tree.
+			var c []Edge
+			for n := f.n; n != h.Node; {
+				e := parent[n.ID()]
+				c = append(c, e)
+				n = adjacent(e, n)
+			}
+			for i, j := 0, len(c)-1; i < j; i, j = i+1, j-1 {
+				c[i], c[j] = c[j], c[i]
+			}
+			return append(c, h.Edge)
+		}
+	}
+
+	return nil
+}
+
+// EulerianPath returns a trail that traverses every edge satisfying the edge filter ef exactly once,
+// as an ordered slice of edges in which consecutive edges share a node. Such a trail exists when the
+// edges satisfying ef are all in one connected component, ignoring nodes with no such edges, and zero
+// or two nodes have odd degree. When no node has odd degree the trail is a circuit, starting and ending
+// at the same node; otherwise it runs between the two odd nodes. A self-loop counts twice toward the
+// degree of its node. If no trail exists, ok is returned false. The trail is found using Hierholzer's
+// algorithm.
+func (g *Undirected) EulerianPath(ef EdgeFilter) (path []Edge, ok bool) {
+	type frame struct {
+		n    Node
+		e    Edge
+		hops []*Hop
+		i    int
+	}
+
+	var (
+		start Node
+		size  int
+		odd   int
+	)
+	deg := make(map[Node]int)
+	for _, e := range g.compEdges {
+		if !ef(e) {
+			continue
+		}
+		size++
+		u, v := e.Nodes()
+		deg[u]++
+		deg[v]++
+	}
+	for _, n := range g.compNodes {
+		d, ok := deg[n]
+		if !ok {
+			continue
+		}
+		if d%2 == 1 {
+			if odd++; odd > 2 {
+				return nil, false
+			}
+			if odd == 1 {
+				start = n
+			}
+		} else if start == nil {
+			start = n
+		}
+	}
+	if odd == 1 {
+		return nil, false
+	}
+	if size == 0 {
+		return nil, true
+	}
+
+	used := make(map[Edge]struct{}, size)
+	stack := []frame{{n: start, hops: start.Hops(ef)}}
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if f.i == len(f.hops) {
+			if f.e != nil {
+				path = append(path, f.e)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		h := f.hops[f.i]
+		f.i++
+		if _, ok := used[h.Edge]; ok {
+			continue
+		}
+		used[h.Edge] = struct{}{}
+		stack = append(stack, frame{n: h.Node, e: h.Edge, hops: h.Node.Hops(ef)})
+	}
+	if len(path) != size {
+		// The edges are not all connected.
+		return nil, false
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, true
+}
+
+// CycleBasis returns a fundamental cycle basis of the graph. A spanning forest is found as for
+// SpanningForest, and each edge not in the forest closes exactly one cycle with the path in the
+// forest between its nodes, so the basis holds Size() - Order() + c cycles for a graph with c
+// connected components. Each cycle is returned as an ordered slice of edges as for FindCycle, ending
+// with the edge not in the forest. Self-loops form single edge cycles and parallel edges form two
+// edge cycles.
+func (g *Undirected) CycleBasis() [][]Edge {
+	forest, _ := g.SpanningForest()
+	inForest := make(map[Edge]struct{}, len(forest))
+	for _, e := range forest {
+		inForest[e] = struct{}{}
+	}
+
+	// Root each tree of the forest to find the parent edge and depth of each node.
+	parent := make(map[int]Edge)
+	depth := make(map[int]int)
+	var visits []bool
+	for _, s := range g.compNodes {
+		if marked(s, visits) {
+			continue
+		}
+		visits = mark(s, visits)
+		depth[s.ID()] = 0
+		stack := []Node{s}
+		for len(stack) > 0 {
+			u := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			for _, e := range u.Edges() {
+				if _, ok := inForest[e]; !ok {
+					continue
+				}
+				v := adjacent(e, u)
+				if marked(v, visits) {
+					continue
+				}
+				visits = mark(v, visits)
+				parent[v.ID()] = e
+				depth[v.ID()] = depth[u.ID()] + 1
+				stack = append(stack, v)
+			}
+		}
+	}
+
+	var basis [][]Edge
+	for _, e := range g.compEdges {
+		if _, ok := inForest[e]; ok {
+			continue
+		}
+		var up, down []Edge
+		u, v := e.Nodes()
+		for u != v {
+			if depth[u.ID()] >= depth[v.ID()] {
+				p := parent[u.ID()]
+				up = append(up, p)
+				u = adjacent(p, u)
+			} else {
+				p := parent[v.ID()]
+				down = append(down, p)
+				v = adjacent(p, v)
+			}
+		}
+		for i, j := 0, len(down)-1; i < j; i, j = i+1, j-1 {
+			down[i], down[j] = down[j], down[i]
+		}
+		c := append(up, down...)
+		basis = append(basis, append(c, e))
+	}
+
+	return basis
+}
@@ -0,0 +1,131 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+)
+
+// Helpers
+func checkCycle(c *check.C, cycle []Edge) {
+	c.Assert(len(cycle) > 0, check.Equals, true)
+	u, v := cycle[0].Nodes()
+	start, n := u, v
+	if len(cycle) > 1 {
+		if a, b := cycle[1].Nodes(); u == a || u == b {
+			start, n = v, u
+		}
+	}
+	for _, e := range cycle[1:] {
+		a, b := e.Nodes()
+		c.Assert(n == a || n == b, check.Equals, true)
+		n = adjacent(e, n)
+	}
+	c.Check(n, check.Equals, start)
+}
+
+// trailEnds checks that consecutive edges of trail share a node and returns the first and last nodes
+// of the trail.
+func trailEnds(c *check.C, trail []Edge) (start, end Node) {
+	c.Assert(len(trail) > 0, check.Equals, true)
+	u, v := trail[0].Nodes()
+	start, end = u, v
+	if len(trail) > 1 {
+		if a, b := trail[1].Nodes(); u == a || u == b {
+			start, end = v, u
+		}
+	}
+	for _, e := range trail[1:] {
+		a, b := e.Nodes()
+		c.Assert(end == a || end == b, check.Equals, true)
+		end = adjacent(e, end)
+	}
+	return start, end
+}
+
+// Tests
+func (s *S) TestFindCycle(c *check.C) {
+	tree := undirected(c, []e{{0, 1}, {0, 2}, {1, 3}, {1, 4}, {2, 5}, {7, 8}})
+	c.Check(tree.HasCycle(all), check.Equals, false)
+	c.Check(tree.FindCycle(all), check.IsNil)
+
+	loop := undirected(c, []e{{0, 1}, {1, 1}})
+	c.Check(loop.HasCycle(all), check.Equals, true)
+	cycle := loop.FindCycle(all)
+	c.Check(len(cycle), check.Equals, 1)
+	c.Check(cycle[0].String(), check.Equals, "1--1")
+
+	par := undirected(c, []e{{0, 1}, {1, 0}})
+	checkCycle(c, par.FindCycle(all))
+
+	g := undirected(c, uv)
+	c.Check(g.HasCycle(all), check.Equals, true)
+	checkCycle(c, g.FindCycle(all))
+	g.DeleteByID(9)
+	checkCycle(c, g.FindCycle(all))
+	c.Check(g.HasCycle(func(e Edge) bool { return e.Head().ID() != 1 && e.Tail().ID() != 2 }), check.Equals, false)
+}
+
+func (s *S) TestEulerianPath(c *check.C) {
+	sq := undirected(c, []e{{0, 1}, {1, 2}, {2, 3}, {3, 0}})
+	sq.AddID(10)
+	trail, ok := sq.EulerianPath(all)
+	c.Assert(ok, check.Equals, true)
+	c.Check(trail, check.HasLen, 4)
+	start, end := trailEnds(c, trail)
+	c.Check(start, check.Equals, end)
+
+	p := path(c, 4)
+	p.ConnectByID(1, 1, 1, 0)
+	trail, ok = p.EulerianPath(all)
+	c.Assert(ok, check.Equals, true)
+	c.Check(trail, check.HasLen, 4)
+	start, end = trailEnds(c, trail)
+	c.Check([]int{start.ID(), end.ID()}, check.DeepEquals, []int{0, 3})
+
+	_, ok = star(c, 3).EulerianPath(all)
+	c.Check(ok, check.Equals, false)
+
+	tris := undirected(c, []e{{0, 1}, {1, 2}, {2, 0}, {3, 4}, {4, 5}, {5, 3}})
+	_, ok = tris.EulerianPath(all)
+	c.Check(ok, check.Equals, false)
+	trail, ok = tris.EulerianPath(func(e Edge) bool { return e.Head().ID() < 3 })
+	c.Check(ok, check.Equals, true)
+	c.Check(trail, check.HasLen, 3)
+}
+
+func (s *S) TestCycleBasis(c *check.C) {
+	// Two triangles joined by a path, with a separate tree component.
+	g := undirected(c, []e{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {3, 4}, {4, 5}, {5, 3}, {7, 8}, {8, 9}})
+	basis := g.CycleBasis()
+	c.Check(basis, check.HasLen, g.Size()-g.Order()+len(g.ConnectedComponents(all)))
+	c.Check(basis, check.HasLen, 2)
+	for _, cycle := range basis {
+		checkCycle(c, cycle)
+	}
+
+	g.ConnectByID(9, 9, 1, 0)
+	g.ConnectByID(7, 8, 1, 0)
+	g.ConnectByID(0, 4, 1, 0)
+	basis = g.CycleBasis()
+	c.Check(basis, check.HasLen, g.Size()-g.Order()+len(g.ConnectedComponents(all)))
+	c.Check(basis, check.HasLen, 5)
+	for _, cycle := range basis {
+		checkCycle(c, cycle)
+	}
+
+	c.Check(undirected(c, []e{{0, 1}, {1, 2}}).CycleBasis(), check.HasLen, 0)
+}
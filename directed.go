@@ -0,0 +1,599 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// NodeNotDirected is returned when a node made for an undirected graph is added to a Directed.
+var NodeNotDirected = errors.New("graph: node is not directed")
+
+// A Directed is a container for a directed graph representation. Edges are directed from their
+// Tail to their Head.
+type Directed struct {
+	nodes, compNodes Nodes
+	edges, compEdges Edges
+}
+
+// NewDirected creates a new empty Directed graph.
+func NewDirected() *Directed {
+	return &Directed{
+		nodes:     Nodes{},
+		compNodes: Nodes{},
+		edges:     Edges{},
+		compEdges: Edges{},
+	}
+}
+
+// NextNodeID returns the next unused available node ID. Unused IDs may be available for nodes with
+// ID in [0, NextNodeID()) from deletion of nodes.
+func (g *Directed) NextNodeID() int {
+	return len(g.nodes)
+}
+
+// NewNode returns a new node with ID NextNodeID(). The node is not added to the graph.
+func (g *Directed) NewNode() Node {
+	return &node{id: len(g.nodes), directed: true}
+}
+
+// NextEdgeID returns the next unused available edge ID.
+func (g *Directed) NextEdgeID() int {
+	return len(g.edges)
+}
+
+// Order returns the number of nodes in the graph.
+func (g *Directed) Order() int {
+	return len(g.compNodes)
+}
+
+// Size returns the number of edges in the graph.
+func (g *Directed) Size() int {
+	return len(g.compEdges)
+}
+
+// Nodes returns the complete set of nodes in the graph.
+func (g *Directed) Nodes() Nodes {
+	return g.compNodes
+}
+
+// Node returns the node with the specified ID.
+func (g *Directed) Node(id int) Node {
+	if id >= len(g.nodes) {
+		return nil
+	}
+	return g.nodes[id]
+}
+
+// Edges returns the complete set of edges in the graph.
+func (g *Directed) Edges() []Edge {
+	return g.compEdges
+}
+
+// Edge returns the edge with the specified ID.
+func (g *Directed) Edge(id int) Edge {
+	if id >= len(g.edges) {
+		return nil
+	}
+	return g.edges[id]
+}
+
+// Node methods
+
+// Add adds a node n to the graph. If a node with already exists in the graph with the same id
+// an error NodeExists is returned. If n was not made for a directed graph, as by NewNode, an error
+// NodeNotDirected is returned.
+func (g *Directed) Add(n Node) error {
+	if dn, ok := n.(*node); !ok || !dn.directed {
+		return NodeNotDirected
+	}
+	id := n.ID()
+	if ok, _ := g.HasNodeID(id); ok {
+		return NodeExists
+	}
+
+	if id == len(g.nodes) {
+		g.nodes = append(g.nodes, n)
+	} else if id > len(g.nodes) {
+		ns := make(Nodes, id+1)
+		copy(ns, g.nodes)
+		g.nodes = ns
+		g.nodes[id] = n
+	} else {
+		g.nodes[id] = n
+	}
+	n.setIndex(len(g.compNodes))
+	g.compNodes = append(g.compNodes, n)
+
+	return nil
+}
+
+// AddID adds a node with a specified ID. If a node with this ID already exists,
+// it is returned with an error NodeExists.
+func (g *Directed) AddID(id int) (Node, error) {
+	if ok, _ := g.HasNodeID(id); ok {
+		return g.Node(id), NodeExists
+	}
+
+	n := &node{id: id, directed: true}
+	g.Add(n)
+
+	return n, nil
+}
+
+// Has returns a boolean indicating whether the node n exists in the graph. If the ID of n is no in
+// [0, NextNodeID()) an error, NodeIDOutOfRange is returned.
+func (g *Directed) Has(n Node) (bool, error) {
+	return g.HasNodeID(n.ID())
+}
+
+// HasNodeID returns a boolean indicating whether a node with ID is exists in the graph. If ID is no in
+// [0, NextNodeID()) an error, NodeIDOutOfRange is returned.
+func (g *Directed) HasNodeID(id int) (bool, error) {
+	if id < 0 || id > len(g.nodes)-1 {
+		return false, NodeIDOutOfRange
+	}
+	return g.nodes[id] != nil, nil
+}
+
+// DeleteByID deletes the node with the specified from the graph. If the specified node does not exist
+// an error, NodeDoesNotExist is returned.
+func (g *Directed) DeleteByID(id int) error {
+	ok, _ := g.HasNodeID(id)
+	if !ok {
+		return NodeDoesNotExist
+	}
+	g.deleteNode(id)
+
+	return nil
+}
+
+// Delete deletes the node n from the graph. If the specified node does not exist an error,
+// NodeDoesNotExist is returned.
+func (g *Directed) Delete(n Node) error {
+	return g.DeleteByID(n.ID())
+}
+
+func (g *Directed) deleteNode(id int) {
+	n := g.nodes[id]
+	g.nodes[n.ID()] = nil
+	for _, e := range append([]Edge(nil), n.Edges()...) {
+		e.disconnect(adjacent(e, n))
+		g.compEdges = g.compEdges.delFromGraph(e.index())
+		g.edges[e.ID()] = nil
+		e.setID(-1)
+	}
+	g.compNodes = g.compNodes.delFromGraph(n.index())
+	n.setID(-1)
+}
+
+// Neighbors returns a slice of nodes that are reachable from the node n via out-edges of n that
+// satisfy the criteria specified by the edge filter ef. If the node does not exist, an error
+// NodeDoesNotExist or NodeIDOutOfRange is returned.
+func (g *Directed) Neighbors(n Node, ef EdgeFilter) ([]Node, error) {
+	ok, err := g.Has(n)
+	if !ok {
+		if err == nil {
+			err = NodeDoesNotExist
+		}
+		return nil, err
+	}
+	return n.OutNeighbors(ef), nil
+}
+
+// Edge methods
+
+// newEdge makes a new edge directed from u to v with weight w, edge flags f and label l. The ID
+// chosen for the edge is NextEdgeID().
+func (g *Directed) newEdge(u, v Node, w float64, f EdgeFlags, l string) Edge {
+	e := newEdge(len(g.edges), len(g.compEdges), u, v, w, f, l)
+	g.edges = append(g.edges, e)
+	g.compEdges = append(g.compEdges, e)
+
+	return e
+}
+
+// newEdgeKeepID makes a new edge directed from u to v with ID id, weight w, edge flags f and label l.
+func (g *Directed) newEdgeKeepID(id int, u, v Node, w float64, f EdgeFlags, l string) Edge {
+	if id < len(g.edges) && g.edges[id] != nil {
+		panic("graph: attempted to create a new edge with an existing ID")
+	}
+	e := newEdge(id, len(g.compEdges), u, v, w, f, l)
+
+	if id == len(g.edges) {
+		g.edges = append(g.edges, e)
+	} else if id > len(g.edges) {
+		es := make(Edges, id+1)
+		copy(es, g.edges)
+		g.edges = es
+		g.edges[id] = e
+	} else {
+		g.edges[id] = e
+	}
+	g.compEdges = append(g.compEdges, e)
+
+	return e
+}
+
+// ConnectWith joins node u to node v with the provided edge, directed from u to v. An error is
+// returned if either of the nodes does not exist.
+func (g *Directed) ConnectWith(u, v Node, with Edge) error {
+	var (
+		ok  bool
+		err error
+	)
+	ok, err = g.Has(u)
+	if !ok {
+		return err
+	}
+	ok, err = g.Has(v)
+	if !ok {
+		return err
+	}
+
+	e := with
+	e.setID(len(g.edges))
+	e.setIndex(len(g.compEdges))
+	e.join(u, v)
+
+	g.edges = append(g.edges, e)
+	g.compEdges = append(g.compEdges, e)
+
+	u.add(e)
+	if v != u {
+		v.add(e)
+	}
+
+	return nil
+}
+
+// Connect creates a new edge directed from node u to node v with weight w, and specifying edge flags
+// f. The new edge is returned on success. An error is returned if either of the nodes does not exist.
+func (g *Directed) Connect(u, v Node, w float64, f EdgeFlags) (Edge, error) {
+	var (
+		ok  bool
+		err error
+	)
+	ok, err = g.Has(u)
+	if !ok {
+		return nil, err
+	}
+	ok, err = g.Has(v)
+	if !ok {
+		return nil, err
+	}
+
+	e := g.newEdge(u, v, w, f, "")
+	u.add(e)
+	if v != u {
+		v.add(e)
+	}
+
+	return e, nil
+}
+
+// ConnectByID creates a new edge directed from the node with ID uid to the node with ID vid with
+// weight w, and specifying edge flags f. The id of the new edge is returned on success. An error is
+// returned if either of the nodes does not exist.
+func (g *Directed) ConnectByID(uid, vid int, w float64, f EdgeFlags) (int, error) {
+	var (
+		ok  bool
+		err error
+	)
+	ok, err = g.HasNodeID(uid)
+	if !ok {
+		return -1, err
+	}
+	ok, err = g.HasNodeID(vid)
+	if !ok {
+		return -1, err
+	}
+
+	e := g.newEdge(g.nodes[uid], g.nodes[vid], w, f, "")
+	g.nodes[uid].add(e)
+	if vid != uid {
+		g.nodes[vid].add(e)
+	}
+
+	return e.ID(), nil
+}
+
+// Connected returns a boolean indicating whether there is an edge directed from node u to node v.
+// A node is considered connected to itself. An error is returned if either of the nodes does not
+// exist.
+func (g *Directed) Connected(u, v Node) (bool, error) {
+	var (
+		ok  bool
+		err error
+	)
+	ok, err = g.Has(u)
+	if !ok {
+		return false, err
+	}
+	ok, err = g.Has(v)
+	if !ok {
+		return false, err
+	}
+
+	if u == v {
+		return true, nil
+	}
+
+	for _, e := range u.Edges() {
+		if e.Tail() == u && e.Head() == v {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// ConnectingEdges returns a slice of edges that are directed from node u to node v. An error is
+// returned if either of the nodes does not exist.
+func (g *Directed) ConnectingEdges(u, v Node) ([]Edge, error) {
+	var (
+		ok  bool
+		err error
+	)
+	ok, err = g.Has(u)
+	if !ok {
+		return nil, err
+	}
+	ok, err = g.Has(v)
+	if !ok {
+		return nil, err
+	}
+
+	var c []Edge
+	for _, e := range u.Edges() {
+		if e.Tail() == u && e.Head() == v {
+			c = append(c, e)
+		}
+	}
+
+	return c, nil
+}
+
+// DeleteEdge deleted the edge e from the graph. An error is returned if the edge does not exist in
+// the graph.
+func (g *Directed) DeleteEdge(e Edge) error {
+	i := e.index()
+	if i < 0 || i > len(g.compEdges)-1 || g.compEdges[i] != e {
+		return EdgeDoesNotExist
+	}
+
+	h, t := e.Head(), e.Tail()
+	e.disconnect(h)
+	if t != h {
+		e.disconnect(t)
+	}
+	g.compEdges = g.compEdges.delFromGraph(i)
+	g.edges[e.ID()] = nil
+	e.setID(-1)
+
+	return nil
+}
+
+// Structure methods
+
+// A CycleError is returned when a directed cycle prevents an operation on a graph. Node is a node
+// that is part of a cycle.
+type CycleError struct {
+	Node Node
+}
+
+func (e *CycleError) Error() string {
+	return fmt.Sprintf("graph: cycle detected at node %d", e.Node.ID())
+}
+
+// TopologicalSort returns the nodes of the graph ordered such that each node appears before all the
+// nodes its out-edges lead to, using Kahn's algorithm. The sort is stable: where there is a choice,
+// nodes with no remaining in-edges are emitted in order of ID and then in the order they become free.
+// If the graph contains a cycle, a *CycleError identifying a node on a cycle is returned.
+func (g *Directed) TopologicalSort() (order []Node, err error) {
+	indeg := make([]int, len(g.nodes))
+	for _, e := range g.compEdges {
+		indeg[e.Head().ID()]++
+	}
+
+	q := &queue{}
+	for _, n := range g.nodes {
+		if n != nil && indeg[n.ID()] == 0 {
+			q.Enqueue(n)
+		}
+	}
+	for q.Len() > 0 {
+		u, _ := q.Dequeue()
+		order = append(order, u)
+		for _, e := range u.Edges() {
+			if e.Tail() != u {
+				continue
+			}
+			v := e.Head()
+			indeg[v.ID()]--
+			if indeg[v.ID()] == 0 {
+				q.Enqueue(v)
+			}
+		}
+	}
+	if len(order) == len(g.compNodes) {
+		return order, nil
+	}
+
+	// Every node left with in-edges has an in-edge from another such node,
+	// so walking backwards from any of them must eventually revisit a node.
+	var n Node
+	for _, u := range g.compNodes {
+		if indeg[u.ID()] > 0 {
+			n = u
+			break
+		}
+	}
+	var seen []bool
+	for !marked(n, seen) {
+		seen = mark(n, seen)
+		for _, e := range n.Edges() {
+			if e.Head() == n && indeg[e.Tail().ID()] > 0 {
+				n = e.Tail()
+				break
+			}
+		}
+	}
+
+	return nil, &CycleError{Node: n}
+}
+
+// Reverse returns a new graph holding the nodes of g and, for each edge of g, an edge directed from
+// its head to its tail. Node and edge IDs, edge weights, flags and labels are preserved. Self-loops
+// are unchanged.
+func (g *Directed) Reverse() *Directed {
+	r := NewDirected()
+	for _, n := range g.nodes {
+		if n != nil {
+			r.AddID(n.ID())
+		}
+	}
+	for _, e := range g.edges {
+		if e == nil {
+			continue
+		}
+		u, v := r.nodes[e.Head().ID()], r.nodes[e.Tail().ID()]
+		ne := r.newEdgeKeepID(e.ID(), u, v, e.Weight(), e.Flags(), e.Label())
+		u.add(ne)
+		if v != u {
+			v.add(ne)
+		}
+	}
+
+	return r
+}
+
+// TransitiveClosure returns a new graph with the same node IDs as g holding an edge of weight 1 from
+// u to v for each pair of nodes where v can be reached from u by following one or more edges of g.
+// A node is therefore joined to itself only if it lies on a cycle, unless reflexive is true, in which
+// case every node is joined to itself. Reachability is found by a breadth-first search from each
+// node. Edges are added in order of tail node ID and then head node ID.
+func (g *Directed) TransitiveClosure(reflexive bool) *Directed {
+	tc := NewDirected()
+	for _, n := range g.nodes {
+		if n != nil {
+			tc.AddID(n.ID())
+		}
+	}
+
+	all := func(_ Edge) bool { return true }
+	reach := make([]bool, len(g.nodes))
+	q := &queue{}
+	for _, u := range g.nodes {
+		if u == nil {
+			continue
+		}
+		for i := range reach {
+			reach[i] = false
+		}
+		q.Enqueue(u)
+		for q.Len() > 0 {
+			n, _ := q.Dequeue()
+			for _, v := range n.OutNeighbors(all) {
+				if !reach[v.ID()] {
+					reach[v.ID()] = true
+					q.Enqueue(v)
+				}
+			}
+		}
+		if reflexive {
+			reach[u.ID()] = true
+		}
+		for id, ok := range reach {
+			if ok {
+				tc.ConnectByID(u.ID(), id, 1, 0)
+			}
+		}
+	}
+
+	return tc
+}
+
+// TransitiveReduction returns a new graph holding the nodes of g and the smallest subset of the edges
+// of g that gives the same reachability between nodes. An edge from u to v is omitted if v can be
+// reached from u by a path through another node, and of parallel edges only the one with the lowest
+// ID is kept. Node and edge IDs, edge weights, flags and labels of retained edges are preserved. The
+// reduction is unique only for acyclic graphs, so if g contains a cycle a nil graph and a *CycleError
+// are returned.
+func (g *Directed) TransitiveReduction() (*Directed, error) {
+	order, err := g.TopologicalSort()
+	if err != nil {
+		return nil, err
+	}
+
+	// Find the nodes reachable from each node, in reverse topological order.
+	reach := make([][]bool, len(g.nodes))
+	for i := len(order) - 1; i >= 0; i-- {
+		u := order[i]
+		r := make([]bool, len(g.nodes))
+		for _, e := range u.Edges() {
+			if e.Tail() != u {
+				continue
+			}
+			v := e.Head().ID()
+			r[v] = true
+			for id, ok := range reach[v] {
+				if ok {
+					r[id] = true
+				}
+			}
+		}
+		reach[u.ID()] = r
+	}
+
+	tr := NewDirected()
+	for _, n := range g.nodes {
+		if n != nil {
+			tr.AddID(n.ID())
+		}
+	}
+	for _, e := range g.edges {
+		if e == nil {
+			continue
+		}
+		u, v := e.Tail(), e.Head()
+		redundant := false
+		for _, f := range u.Edges() {
+			if f.Tail() != u {
+				continue
+			}
+			w := f.Head()
+			if w == v && f.ID() < e.ID() || w != v && reach[w.ID()][v.ID()] {
+				redundant = true
+				break
+			}
+		}
+		if redundant {
+			continue
+		}
+		tu, tv := tr.nodes[u.ID()], tr.nodes[v.ID()]
+		ne := tr.newEdgeKeepID(e.ID(), tu, tv, e.Weight(), e.Flags(), e.Label())
+		tu.add(ne)
+		tv.add(ne)
+	}
+
+	return tr, nil
+}
+
+func (g *Directed) String() string {
+	return fmt.Sprintf("D:|V|=%d |E|=%d", g.Order(), g.Size())
+}
@@ -0,0 +1,287 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+)
+
+// Tests
+var (
+	dag = []e{
+		{5, 11},
+		{7, 11},
+		{7, 8},
+		{3, 8},
+		{3, 10},
+		{11, 2},
+		{11, 9},
+		{11, 10},
+		{8, 9},
+	}
+	dagOrder = []int{3, 5, 7, 11, 8, 2, 10, 9}
+)
+
+func directed(c *check.C, edges []e) (g *Directed) {
+	g = NewDirected()
+	for _, e := range edges {
+		u, _ := g.AddID(e.u)
+		v, _ := g.AddID(e.v)
+		g.Connect(u, v, 1, 0)
+	}
+
+	return
+}
+
+func (s *S) TestDirectedTopologicalSort(c *check.C) {
+	g := directed(c, dag)
+	order, err := g.TopologicalSort()
+	c.Assert(err, check.IsNil)
+	var ids []int
+	for _, n := range order {
+		ids = append(ids, n.ID())
+	}
+	c.Check(ids, check.DeepEquals, dagOrder)
+
+	pos := make(map[int]int)
+	for i, id := range ids {
+		pos[id] = i
+	}
+	for _, e := range g.Edges() {
+		c.Check(pos[e.Tail().ID()] < pos[e.Head().ID()], check.Equals, true)
+	}
+}
+
+func (s *S) TestDirectedTopologicalSortCycle(c *check.C) {
+	g := directed(c, append(dag, e{9, 3}, e{2, 1}))
+	order, err := g.TopologicalSort()
+	c.Check(order, check.IsNil)
+	ce, ok := err.(*CycleError)
+	c.Assert(ok, check.Equals, true)
+	onCycle := map[int]bool{3: true, 8: true, 9: true}
+	c.Check(onCycle[ce.Node.ID()], check.Equals, true)
+
+	g = directed(c, []e{{0, 1}, {1, 1}})
+	_, err = g.TopologicalSort()
+	c.Assert(err, check.NotNil)
+	c.Check(err.(*CycleError).Node.ID(), check.Equals, 1)
+}
+
+func (s *S) TestDirectedReverse(c *check.C) {
+	g := directed(c, dag)
+	g.ConnectByID(8, 8, 2, EdgeCut)
+	g.Edge(1).SetWeight(3)
+	r := g.Reverse()
+	c.Check(r.Order(), check.Equals, g.Order())
+	c.Check(r.Size(), check.Equals, g.Size())
+	for _, n := range g.Nodes() {
+		rn := r.Node(n.ID())
+		c.Check(rn.OutDegree(all), check.Equals, n.InDegree(all))
+		c.Check(rn.InDegree(all), check.Equals, n.OutDegree(all))
+	}
+	for _, e := range g.Edges() {
+		re := r.Edge(e.ID())
+		c.Check(re.Tail().ID(), check.Equals, e.Head().ID())
+		c.Check(re.Head().ID(), check.Equals, e.Tail().ID())
+		c.Check(re.Weight(), check.Equals, e.Weight())
+		c.Check(re.Flags(), check.Equals, e.Flags())
+	}
+}
+
+func (s *S) TestDirectedTransitiveClosure(c *check.C) {
+	const n = 5
+	var chain []e
+	for i := 0; i < n-1; i++ {
+		chain = append(chain, e{i, i + 1})
+	}
+	g := directed(c, chain)
+	tc := g.TransitiveClosure(false)
+	c.Check(tc.Order(), check.Equals, n)
+	c.Check(tc.Size(), check.Equals, n*(n-1)/2)
+	for u := 0; u < n; u++ {
+		for v := 0; v < n; v++ {
+			ce, err := tc.ConnectingEdges(tc.Node(u), tc.Node(v))
+			c.Assert(err, check.IsNil)
+			c.Check(len(ce) == 1, check.Equals, u < v, check.Commentf("%d->%d", u, v))
+		}
+	}
+
+	c.Check(g.TransitiveClosure(true).Size(), check.Equals, n*(n-1)/2+n)
+
+	g.ConnectByID(n-1, 1, 1, 0)
+	tc = g.TransitiveClosure(false)
+	for u := 0; u < n; u++ {
+		ce, err := tc.ConnectingEdges(tc.Node(u), tc.Node(u))
+		c.Assert(err, check.IsNil)
+		c.Check(len(ce) == 1, check.Equals, u > 0, check.Commentf("%d->%d", u, u))
+	}
+}
+
+func (s *S) TestDirectedTransitiveReduction(c *check.C) {
+	g := directed(c, dag)
+	// Shortcuts implied by paths through other nodes, and a parallel edge.
+	shortcuts := []e{{5, 2}, {7, 9}, {3, 9}, {5, 10}, {7, 11}}
+	for _, s := range shortcuts {
+		g.ConnectByID(s.u, s.v, 1, 0)
+	}
+	tr, err := g.TransitiveReduction()
+	c.Assert(err, check.IsNil)
+	c.Check(tr.Order(), check.Equals, g.Order())
+	c.Check(tr.Size(), check.Equals, len(dag))
+	for _, e := range tr.Edges() {
+		c.Check(e.ID() < len(dag), check.Equals, true)
+		ge := g.Edge(e.ID())
+		c.Check(e.Tail().ID(), check.Equals, ge.Tail().ID())
+		c.Check(e.Head().ID(), check.Equals, ge.Head().ID())
+	}
+
+	want := g.TransitiveClosure(false)
+	got := tr.TransitiveClosure(false)
+	c.Check(got.Size(), check.Equals, want.Size())
+	for _, e := range want.Edges() {
+		ce, err := got.ConnectingEdges(got.Node(e.Tail().ID()), got.Node(e.Head().ID()))
+		c.Assert(err, check.IsNil)
+		c.Check(ce, check.HasLen, 1)
+	}
+
+	g = directed(c, append(dag, e{9, 3}))
+	tr, err = g.TransitiveReduction()
+	c.Check(tr, check.IsNil)
+	_, ok := err.(*CycleError)
+	c.Check(ok, check.Equals, true)
+}
+
+func (s *S) TestDirected(c *check.C) {
+	g := directed(c, dag)
+	c.Check(g.Order(), check.Equals, 8)
+	c.Check(g.Size(), check.Equals, len(dag))
+
+	out := map[int]int{}
+	in := map[int]int{}
+	for _, e := range g.Edges() {
+		out[e.Tail().ID()]++
+		in[e.Head().ID()]++
+	}
+	c.Check(out, check.DeepEquals, map[int]int{5: 1, 7: 2, 3: 2, 11: 3, 8: 1})
+	c.Check(in, check.DeepEquals, map[int]int{11: 2, 8: 2, 10: 2, 2: 1, 9: 2})
+	for _, n := range g.Nodes() {
+		ns, err := g.Neighbors(n, all)
+		c.Assert(err, check.IsNil)
+		c.Check(len(ns), check.Equals, out[n.ID()])
+		c.Check(n.Degree(), check.Equals, out[n.ID()]+in[n.ID()])
+	}
+
+	ok, _ := g.Connected(g.Node(11), g.Node(2))
+	c.Check(ok, check.Equals, true)
+	ok, _ = g.Connected(g.Node(2), g.Node(11))
+	c.Check(ok, check.Equals, false)
+	ce, _ := g.ConnectingEdges(g.Node(10), g.Node(3))
+	c.Check(ce, check.HasLen, 0)
+
+	c.Assert(g.DeleteByID(11), check.IsNil)
+	c.Check(g.Order(), check.Equals, 7)
+	c.Check(g.Size(), check.Equals, 4)
+	c.Check(g.Node(2).Degree(), check.Equals, 0)
+	ce, _ = g.ConnectingEdges(g.Node(3), g.Node(8))
+	c.Assert(ce, check.HasLen, 1)
+	c.Assert(g.DeleteEdge(ce[0]), check.IsNil)
+	c.Check(g.Size(), check.Equals, 3)
+	c.Check(g.Node(3).Degree(), check.Equals, 1)
+	c.Check(g.DeleteEdge(ce[0]), check.Equals, EdgeDoesNotExist)
+
+	id, err := g.ConnectByID(3, 3, 1, 0)
+	c.Assert(err, check.IsNil)
+	c.Assert(g.DeleteEdge(g.Edge(id)), check.IsNil)
+	c.Check(g.Size(), check.Equals, 3)
+	c.Check(g.Node(3).Degree(), check.Equals, 1)
+	c.Check(g.Edge(id), check.IsNil)
+
+	other := directed(c, []e{{0, 1}})
+	c.Check(g.DeleteEdge(other.Edge(0)), check.Equals, EdgeDoesNotExist)
+	c.Check(other.Size(), check.Equals, 1)
+}
+
+func (s *S) TestDirectedDegree(c *check.C) {
+	g := directed(c, []e{{0, 1}, {1, 2}, {2, 0}, {0, 2}})
+	for _, t := range []struct {
+		id      int
+		out, in int
+		outN    []int
+		inN     []int
+	}{
+		{0, 2, 1, []int{1, 2}, []int{2}},
+		{1, 1, 1, []int{2}, []int{0}},
+		{2, 1, 2, []int{0}, []int{1, 0}},
+	} {
+		n := g.Node(t.id)
+		c.Check(n.OutDegree(all), check.Equals, t.out)
+		c.Check(n.InDegree(all), check.Equals, t.in)
+		c.Check(n.OutDegree(all)+n.InDegree(all), check.Equals, n.Degree())
+		var outN, inN []int
+		for _, m := range n.OutNeighbors(all) {
+			outN = append(outN, m.ID())
+		}
+		for _, m := range n.InNeighbors(all) {
+			inN = append(inN, m.ID())
+		}
+		c.Check(outN, check.DeepEquals, t.outN)
+		c.Check(inN, check.DeepEquals, t.inN)
+	}
+	g.ConnectByID(1, 1, 1, 0)
+	c.Check(g.Node(1).OutDegree(all), check.Equals, 2)
+	c.Check(g.Node(1).InDegree(all), check.Equals, 2)
+	c.Check(g.Node(0).OutDegree(func(e Edge) bool { return e.Head().ID() != 2 }), check.Equals, 1)
+
+	u := weightedUndirected(c, []we{{0, 1, 1}, {1, 2, 1}, {2, 2, 1}})
+	for _, n := range u.Nodes() {
+		c.Check(n.OutDegree(all), check.Equals, n.Degree())
+		c.Check(n.InDegree(all), check.Equals, n.Degree())
+		c.Check(n.OutNeighbors(all), check.HasLen, n.OutDegree(all))
+		c.Check(n.InNeighbors(all), check.HasLen, n.InDegree(all))
+		c.Check(n.InNeighbors(all), check.DeepEquals, n.OutNeighbors(all))
+	}
+	loop := u.Node(2)
+	c.Check(loop.OutNeighbors(all), check.DeepEquals, []Node{u.Node(1), loop, loop})
+	c.Check(loop.Neighbors(all), check.DeepEquals, []Node{u.Node(1), loop})
+}
+
+func (s *S) TestDirectedTraversal(c *check.C) {
+	g := directed(c, []e{{1, 0}, {1, 2}, {2, 3}, {3, 3}})
+	reached := func(from int) []int {
+		var ids []int
+		NewBreadthFirst().Search(g.Node(from), all, func(n Node) bool { ids = append(ids, n.ID()); return false }, nil)
+		return ids
+	}
+	c.Check(reached(0), check.DeepEquals, []int{0})
+	c.Check(reached(1), check.DeepEquals, []int{1, 0, 2, 3})
+	c.Check(reached(3), check.DeepEquals, []int{3})
+
+	_, err := NewBreadthFirst().Path(g.Node(0), g.Node(1), all)
+	c.Check(err, check.Equals, notFound)
+	p, err := NewBreadthFirst().Path(g.Node(1), g.Node(3), all)
+	c.Assert(err, check.IsNil)
+	c.Check(p, check.HasLen, 2)
+
+	var dfs []int
+	NewDepthFirst().Search(g.Node(2), all, func(n Node) bool { dfs = append(dfs, n.ID()); return false }, nil)
+	c.Check(dfs, check.DeepEquals, []int{2, 3})
+
+	c.Check(g.Node(3).Neighbors(all), check.DeepEquals, []Node{g.Node(3)})
+	c.Check(g.Node(2).Hops(all), check.HasLen, 1)
+
+	c.Check(g.Add(NewUndirected().NewNode()), check.Equals, NodeNotDirected)
+	c.Check(g.Add(g.NewNode()), check.IsNil)
+}
@@ -0,0 +1,506 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// WriteDOT writes a GraphViz DOT representation of the graph to w as an undirected graph with the
+// given name, which may be empty. Nodes are written in order of ID and are labelled by ID, followed by
+// edges in order of ID, written as "u -- v" with a weight attribute and a label attribute if the edge
+// is labelled. Edges with the EdgeCut flag set are given a dashed style.
+func (g *Undirected) WriteDOT(w io.Writer, name string) error {
+	return writeDOT(w, "graph", "--", name, g.nodes, g.edges)
+}
+
+// WriteDOT writes a GraphViz DOT representation of the graph to w as a directed graph with the given
+// name, which may be empty. Nodes are written in order of ID and are labelled by ID, followed by edges
+// in order of ID, written as "tail -> head" with a weight attribute and a label attribute if the edge
+// is labelled. Edges with the EdgeCut flag set are given a dashed style.
+func (g *Directed) WriteDOT(w io.Writer, name string) error {
+	return writeDOT(w, "digraph", "->", name, g.nodes, g.edges)
+}
+
+func writeDOT(w io.Writer, kind, op, name string, nodes Nodes, edges Edges) error {
+	bw := bufio.NewWriter(w)
+	if name == "" {
+		fmt.Fprintf(bw, "%s {\n", kind)
+	} else {
+		fmt.Fprintf(bw, "%s %s {\n", kind, dotID(name))
+	}
+	for _, n := range nodes {
+		if n != nil {
+			fmt.Fprintf(bw, "\t%d;\n", n.ID())
+		}
+	}
+	for _, e := range edges {
+		if e == nil {
+			continue
+		}
+		fmt.Fprintf(bw, "\t%d %s %d [weight=%s", e.Tail().ID(), op, e.Head().ID(), dotFloat(e.Weight()))
+		if l := e.Label(); l != "" {
+			fmt.Fprintf(bw, ", label=%s", dotQuote(l))
+		}
+		if e.Flags()&EdgeCut != 0 {
+			fmt.Fprint(bw, ", style=dashed")
+		}
+		fmt.Fprint(bw, "];\n")
+	}
+	fmt.Fprint(bw, "}\n")
+
+	return bw.Flush()
+}
+
+// dotID returns s as a DOT ID, quoting it if it is not a valid unquoted identifier or is a DOT
+// keyword.
+func dotID(s string) string {
+	if s == "" || dotKeywords[strings.ToLower(s)] {
+		return dotQuote(s)
+	}
+	for i, r := range s {
+		switch {
+		case r == '_', 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', r >= 0200:
+		case '0' <= r && r <= '9' && i > 0:
+		default:
+			return dotQuote(s)
+		}
+	}
+	return s
+}
+
+// dotKeywords holds the DOT keywords, which may not be used as unquoted IDs in any case.
+var dotKeywords = map[string]bool{
+	"node":     true,
+	"edge":     true,
+	"graph":    true,
+	"digraph":  true,
+	"subgraph": true,
+	"strict":   true,
+}
+
+// dotFloat returns f formatted as a DOT ID. NaN and infinities are quoted since they are not
+// valid DOT numerals.
+func dotFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return dotQuote(s)
+	}
+	return s
+}
+
+// dotQuote returns s as a quoted DOT string. Only double quotes and backslashes are escaped.
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// ReadDOT reads a simple undirected GraphViz DOT graph from r and returns the graph it describes with
+// a table mapping the node names used in the DOT source to node IDs. Names that are non-negative
+// integers are used as node IDs directly, and other names are given IDs above the largest integer
+// name in order of their first appearance. A weight attribute on an edge is used as the edge's weight,
+// otherwise the weight is 1, a label attribute is used as the edge's label and edges with a dashed
+// style have the EdgeCut flag set. Other attributes, and graph, node and edge default statements, are
+// ignored. Subgraphs are not supported.
+func ReadDOT(r io.Reader) (*Undirected, map[string]int, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	p := &dotParser{lex: &dotLexer{data: []rune(string(b)), line: 1}}
+	if err = p.parse(); err != nil {
+		return nil, nil, err
+	}
+
+	names := make(map[string]int, len(p.names))
+	next := 0
+	for _, n := range p.names {
+		if id, err := strconv.Atoi(n); err == nil && id >= 0 && strconv.Itoa(id) == n {
+			names[n] = id
+			if id >= next {
+				next = id + 1
+			}
+		}
+	}
+	for _, n := range p.names {
+		if _, ok := names[n]; !ok {
+			names[n] = next
+			next++
+		}
+	}
+
+	g := NewUndirected()
+	for _, n := range p.names {
+		if names[n] > MaxReadID {
+			return nil, nil, fmt.Errorf("graph: dot: node ID %d exceeds MaxReadID", names[n])
+		}
+		if _, err := g.AddID(names[n]); err != nil {
+			return nil, nil, err
+		}
+	}
+	for _, e := range p.edges {
+		u, v := g.nodes[names[e.u]], g.nodes[names[e.v]]
+		ne := g.newEdge(u, v, e.w, e.f, e.l)
+		u.add(ne)
+		if v != u {
+			v.add(ne)
+		}
+	}
+
+	return g, names, nil
+}
+
+type dotEdge struct {
+	u, v string
+	w    float64
+	f    EdgeFlags
+	l    string
+}
+
+type dotParser struct {
+	lex   *dotLexer
+	names []string
+	seen  map[string]bool
+	edges []dotEdge
+}
+
+func (p *dotParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("graph: dot: line %d: %s", p.lex.line, fmt.Sprintf(format, args...))
+}
+
+func (p *dotParser) expect(want string) error {
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	if t.kind != want && !(t.kind == dotIdent && t.text == want) {
+		return p.errorf("expected %q, found %q", want, t.text)
+	}
+	return nil
+}
+
+func (p *dotParser) parse() error {
+	p.seen = make(map[string]bool)
+	t, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	if t.kind == dotIdent && t.text == "strict" {
+		if t, err = p.lex.next(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case t.kind == dotIdent && t.text == "digraph":
+		return p.errorf("directed graphs are not supported")
+	case t.kind != dotIdent || t.text != "graph":
+		return p.errorf("expected \"graph\", found %q", t.text)
+	}
+	if t, err = p.lex.next(); err != nil {
+		return err
+	}
+	if t.kind == dotIdent || t.kind == dotQuoted {
+		if t, err = p.lex.next(); err != nil {
+			return err
+		}
+	}
+	if t.kind != "{" {
+		return p.errorf("expected \"{\", found %q", t.text)
+	}
+
+	for {
+		t, err = p.lex.next()
+		if err != nil {
+			return err
+		}
+		switch t.kind {
+		case "}":
+			if t, err = p.lex.next(); err != io.EOF {
+				if err == nil {
+					err = p.errorf("unexpected %q after graph", t.text)
+				}
+				return err
+			}
+			return nil
+		case ";":
+			continue
+		case dotIdent, dotQuoted:
+			if err = p.statement(t); err != nil {
+				return err
+			}
+		default:
+			return p.errorf("unexpected %q", t.text)
+		}
+	}
+}
+
+func (p *dotParser) statement(t dotToken) error {
+	if t.kind == dotIdent {
+		switch t.text {
+		case "graph", "node", "edge":
+			_, err := p.attributes()
+			return err
+		case "subgraph":
+			return p.errorf("subgraphs are not supported")
+		}
+	}
+
+	nodes := []string{t.text}
+	for {
+		n, err := p.lex.peek()
+		if err != nil {
+			return err
+		}
+		switch n.kind {
+		case "--":
+			p.lex.next()
+			u, err := p.lex.next()
+			if err != nil {
+				return err
+			}
+			if u.kind != dotIdent && u.kind != dotQuoted {
+				return p.errorf("expected node name, found %q", u.text)
+			}
+			nodes = append(nodes, u.text)
+			continue
+		case "->":
+			return p.errorf("directed edges are not supported")
+		case "=":
+			// Graph attribute assignment.
+			if len(nodes) != 1 {
+				return p.errorf("unexpected %q", n.text)
+			}
+			p.lex.next()
+			if v, err := p.lex.next(); err != nil {
+				return err
+			} else if v.kind != dotIdent && v.kind != dotQuoted {
+				return p.errorf("expected attribute value, found %q", v.text)
+			}
+			return nil
+		}
+		break
+	}
+
+	attrs, err := p.attributes()
+	if err != nil {
+		return err
+	}
+	for _, n := range nodes {
+		if !p.seen[n] {
+			p.seen[n] = true
+			p.names = append(p.names, n)
+		}
+	}
+	if len(nodes) == 1 {
+		return nil
+	}
+
+	e := dotEdge{w: 1}
+	if w, ok := attrs["weight"]; ok {
+		e.w, err = strconv.ParseFloat(w, 64)
+		if err != nil {
+			return p.errorf("invalid weight %q", w)
+		}
+	}
+	if attrs["style"] == "dashed" {
+		e.f |= EdgeCut
+	}
+	e.l = attrs["label"]
+	for i := 1; i < len(nodes); i++ {
+		e.u, e.v = nodes[i-1], nodes[i]
+		p.edges = append(p.edges, e)
+	}
+
+	return nil
+}
+
+// attributes reads any attribute lists following a statement.
+func (p *dotParser) attributes() (map[string]string, error) {
+	attrs := make(map[string]string)
+	for {
+		t, err := p.lex.peek()
+		if err != nil || t.kind != "[" {
+			return attrs, err
+		}
+		p.lex.next()
+		for {
+			k, err := p.lex.next()
+			if err != nil {
+				return nil, err
+			}
+			switch k.kind {
+			case "]":
+			case ",", ";":
+				continue
+			case dotIdent, dotQuoted:
+				if err = p.expect("="); err != nil {
+					return nil, err
+				}
+				v, err := p.lex.next()
+				if err != nil {
+					return nil, err
+				}
+				if v.kind != dotIdent && v.kind != dotQuoted {
+					return nil, p.errorf("expected attribute value, found %q", v.text)
+				}
+				attrs[k.text] = v.text
+				continue
+			default:
+				return nil, p.errorf("unexpected %q in attribute list", k.text)
+			}
+			break
+		}
+	}
+}
+
+const (
+	dotIdent  = "ident"
+	dotQuoted = "quoted"
+)
+
+type dotToken struct {
+	kind string
+	text string
+}
+
+type dotLexer struct {
+	data   []rune
+	pos    int
+	line   int
+	peeked *dotToken
+}
+
+var unexpectedEOF = errors.New("graph: dot: unexpected end of input")
+
+func (l *dotLexer) peek() (dotToken, error) {
+	if l.peeked == nil {
+		t, err := l.next()
+		if err != nil {
+			if err == io.EOF {
+				err = unexpectedEOF
+			}
+			return t, err
+		}
+		l.peeked = &t
+	}
+	return *l.peeked, nil
+}
+
+func (l *dotLexer) next() (dotToken, error) {
+	if l.peeked != nil {
+		t := *l.peeked
+		l.peeked = nil
+		return t, nil
+	}
+	if err := l.skip(); err != nil {
+		return dotToken{}, err
+	}
+	if l.pos == len(l.data) {
+		return dotToken{}, io.EOF
+	}
+
+	r := l.data[l.pos]
+	switch {
+	case r == '-' && l.pos+1 < len(l.data) && (l.data[l.pos+1] == '-' || l.data[l.pos+1] == '>'):
+		l.pos += 2
+		op := string(l.data[l.pos-2 : l.pos])
+		return dotToken{kind: op, text: op}, nil
+	case r == '"':
+		var buf []rune
+		for l.pos++; l.pos < len(l.data); l.pos++ {
+			switch r := l.data[l.pos]; r {
+			case '"':
+				l.pos++
+				return dotToken{kind: dotQuoted, text: string(buf)}, nil
+			case '\\':
+				if l.pos+1 < len(l.data) && (l.data[l.pos+1] == '"' || l.data[l.pos+1] == '\\') {
+					l.pos++
+					r = l.data[l.pos]
+				}
+				buf = append(buf, r)
+			case '\n':
+				l.line++
+				fallthrough
+			default:
+				buf = append(buf, r)
+			}
+		}
+		return dotToken{}, fmt.Errorf("graph: dot: line %d: unterminated string", l.line)
+	case r == '_' || r == '-' || r == '.' || unicode.IsLetter(r) || unicode.IsDigit(r):
+		start := l.pos
+		for l.pos++; l.pos < len(l.data); l.pos++ {
+			r := l.data[l.pos]
+			if r != '_' && r != '.' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				break
+			}
+		}
+		return dotToken{kind: dotIdent, text: string(l.data[start:l.pos])}, nil
+	case r == '{' || r == '}' || r == '[' || r == ']' || r == '=' || r == ';' || r == ',':
+		l.pos++
+		return dotToken{kind: string(r), text: string(r)}, nil
+	}
+
+	return dotToken{}, fmt.Errorf("graph: dot: line %d: unexpected character %q", l.line, r)
+}
+
+// skip advances the lexer past white space and comments.
+func (l *dotLexer) skip() error {
+	bol := l.pos == 0
+	for l.pos < len(l.data) {
+		r := l.data[l.pos]
+		switch {
+		case r == '\n':
+			l.line++
+			l.pos++
+			bol = true
+			continue
+		case unicode.IsSpace(r):
+			l.pos++
+			continue
+		case r == '#' && bol, r == '/' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '/':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' {
+				l.pos++
+			}
+			continue
+		case r == '/' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '*':
+			line := l.line
+			for l.pos += 2; ; l.pos++ {
+				if l.pos+1 >= len(l.data) {
+					return fmt.Errorf("graph: dot: line %d: unterminated comment", line)
+				}
+				if l.data[l.pos] == '\n' {
+					l.line++
+				}
+				if l.data[l.pos] == '*' && l.data[l.pos+1] == '/' {
+					l.pos += 2
+					break
+				}
+			}
+			continue
+		}
+		return nil
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"bytes"
+	check "launchpad.net/gocheck"
+	"math"
+	"strings"
+)
+
+// Tests
+var (
+	dotUndirected = `graph tiny {
+	0;
+	1;
+	2;
+	4;
+	0 -- 1 [weight=1];
+	1 -- 2 [weight=2.5, style=dashed];
+	2 -- 0 [weight=-3];
+	4 -- 4 [weight=1];
+}
+`
+	dotDirected = `digraph "a tiny graph" {
+	0;
+	1;
+	2;
+	0 -> 1 [weight=1];
+	2 -> 1 [weight=2];
+}
+`
+)
+
+func (s *S) TestWriteDOT(c *check.C) {
+	g := weightedUndirected(c, []we{{0, 1, 1}, {1, 2, 2.5}, {2, 0, -3}, {4, 4, 1}})
+	g.Edge(1).SetFlags(EdgeCut)
+	var buf bytes.Buffer
+	c.Assert(g.WriteDOT(&buf, "tiny"), check.IsNil)
+	c.Check(buf.String(), check.Equals, dotUndirected)
+
+	d := NewDirected()
+	for i := 0; i < 3; i++ {
+		d.AddID(i)
+	}
+	d.ConnectByID(0, 1, 1, 0)
+	d.ConnectByID(2, 1, 2, 0)
+	buf.Reset()
+	c.Assert(d.WriteDOT(&buf, "a tiny graph"), check.IsNil)
+	c.Check(buf.String(), check.Equals, dotDirected)
+}
+
+func (s *S) TestDOTLabels(c *check.C) {
+	g := undirected(c, []e{{0, 1}, {0, 1}, {1, 2}})
+	g.Edge(0).SetLabel("likes")
+	g.Edge(1).SetLabel(`say "hi" \ bye`)
+	var buf bytes.Buffer
+	c.Assert(g.WriteDOT(&buf, ""), check.IsNil)
+
+	r, _, err := ReadDOT(&buf)
+	c.Assert(err, check.IsNil)
+	c.Assert(r.Size(), check.Equals, g.Size())
+	for _, e := range g.Edges() {
+		c.Check(r.Edge(e.ID()).Label(), check.Equals, e.Label())
+	}
+}
+
+func (s *S) TestDOTQuoting(c *check.C) {
+	for _, t := range []struct {
+		id   string
+		want string
+	}{
+		{"tiny", "tiny"},
+		{"nodes", "nodes"},
+		{"node", `"node"`},
+		{"Edge", `"Edge"`},
+		{"GRAPH", `"GRAPH"`},
+		{"digraph", `"digraph"`},
+		{"subgraph", `"subgraph"`},
+		{"strict", `"strict"`},
+		{"a tiny graph", `"a tiny graph"`},
+		{"", `""`},
+	} {
+		c.Check(dotID(t.id), check.Equals, t.want)
+	}
+
+	g := weightedUndirected(c, []we{{0, 1, math.NaN()}, {1, 2, math.Inf(1)}, {2, 0, math.Inf(-1)}})
+	var buf bytes.Buffer
+	c.Assert(g.WriteDOT(&buf, "strict"), check.IsNil)
+	c.Check(buf.String(), check.Equals, `graph "strict" {
+	0;
+	1;
+	2;
+	0 -- 1 [weight="NaN"];
+	1 -- 2 [weight="+Inf"];
+	2 -- 0 [weight="-Inf"];
+}
+`)
+	r, _, err := ReadDOT(&buf)
+	c.Assert(err, check.IsNil)
+	c.Check(math.IsNaN(r.Edge(0).Weight()), check.Equals, true)
+	c.Check(r.Edge(1).Weight(), check.Equals, math.Inf(1))
+	c.Check(r.Edge(2).Weight(), check.Equals, math.Inf(-1))
+}
+
+func (s *S) TestReadDOT(c *check.C) {
+	g, names, err := ReadDOT(strings.NewReader(dotUndirected))
+	c.Assert(err, check.IsNil)
+	c.Check(len(names), check.Equals, 4)
+	var buf bytes.Buffer
+	c.Assert(g.WriteDOT(&buf, "tiny"), check.IsNil)
+	c.Check(buf.String(), check.Equals, dotUndirected)
+
+	g, names, err = ReadDOT(strings.NewReader(`/* a chain */
+strict graph {
+	node [shape=box];
+	rankdir=LR;
+	# line comment
+	a -- b -- "c d" [weight=2]; // trailing comment
+	3 -- a
+}`))
+	c.Assert(err, check.IsNil)
+	c.Check(names, check.DeepEquals, map[string]int{"3": 3, "a": 4, "b": 5, "c d": 6})
+	c.Check(g.Order(), check.Equals, 4)
+	c.Check(g.Size(), check.Equals, 3)
+	c.Check(g.Edge(1).Weight(), check.Equals, 2.)
+	c.Check(g.Edge(2).Weight(), check.Equals, 1.)
+
+	for _, t := range []struct {
+		dot string
+		err string
+	}{
+		{dotDirected, "graph: dot: line 1: directed graphs are not supported"},
+		{"graph {\n\ta -- b [weight=x];\n}", `graph: dot: line 2: invalid weight "x"`},
+		{"graph {\n\ta -- ;\n}", `graph: dot: line 2: expected node name, found ";"`},
+		{"graph {\n\ta -- b", "graph: dot: unexpected end of input"},
+		{"graph { 400000000 }", "graph: dot: node ID 400000000 exceeds MaxReadID"},
+	} {
+		_, _, err = ReadDOT(strings.NewReader(t.dot))
+		c.Check(err, check.ErrorMatches, t.err)
+	}
+}
@@ -26,6 +26,8 @@ type Edge interface {
 	ID() int
 	Weight() float64
 	SetWeight(float64)
+	Label() string
+	SetLabel(string)
 	Nodes() (u, v Node)
 	Head() Node
 	Tail() Node
@@ -59,6 +61,7 @@ type edge struct {
 	i      int
 	u, v   Node
 	weight float64
+	label  string
 	flags  EdgeFlags
 }
 
@@ -68,8 +71,8 @@ func NewEdge() Edge {
 }
 
 // newEdge returns a new edge.
-func newEdge(id, i int, u, v Node, w float64, f EdgeFlags) Edge {
-	return &edge{id: id, i: i, u: u, v: v, weight: w, flags: f}
+func newEdge(id, i int, u, v Node, w float64, f EdgeFlags, l string) Edge {
+	return &edge{id: id, i: i, u: u, v: v, weight: w, flags: f, label: l}
 }
 
 // ID returns the id of the edge.
@@ -116,6 +119,16 @@ func (e *edge) SetWeight(w float64) {
 	e.weight = w
 }
 
+// Label returns the label of the edge. Labels can be used to distinguish parallel edges.
+func (e *edge) Label() string {
+	return e.label
+}
+
+// SetLabel sets the label of the edge to l.
+func (e *edge) SetLabel(l string) {
+	e.label = l
+}
+
 // Flags returns the flags value for the edge. One flag is currently defined, EdgeCut.
 func (e *edge) Flags() EdgeFlags {
 	return e.flags
@@ -183,3 +196,11 @@ func (e Edges) delFromNode(i int) Edges {
 	e[i], e[len(e)-1] = e[len(e)-1], e[i]
 	return e[:len(e)-1]
 }
+
+// adjacent returns the node joined to n by the edge e.
+func adjacent(e Edge, n Node) Node {
+	if a := e.Tail(); a != n {
+		return a
+	}
+	return e.Head()
+}
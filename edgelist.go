@@ -0,0 +1,122 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ReadEdgeList reads a graph from a plain text edge list. Each line holds the IDs of the two nodes
+// joined by an edge, u and v, separated by white space and, if weighted is true, followed by the
+// weight of the edge. If weighted is false, edges are given a weight of 1. Blank lines and lines
+// beginning with '#' are ignored. Nodes are created as they are first named and repeated pairs
+// result in parallel edges.
+func ReadEdgeList(r io.Reader, weighted bool) (*Undirected, error) {
+	fields := 2
+	if weighted {
+		fields = 3
+	}
+
+	g := NewUndirected()
+	err := scanEdgeList(r, fields, func(u, v int, w float64) error {
+		for _, id := range [2]int{u, v} {
+			if id > MaxReadID {
+				return fmt.Errorf("graph: edge list: node ID %d exceeds MaxReadID", id)
+			}
+			if _, err := g.AddID(id); err != nil && err != NodeExists {
+				return err
+			}
+		}
+		_, err := g.ConnectByID(u, v, w, 0)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// StreamEdges reads a plain text edge list as ReadEdgeList does, calling fn with the node IDs and
+// weight of each edge in turn rather than building a graph, so that arbitrarily large edge lists can be
+// filtered or aggregated. Each line may hold either two or three fields; edges without a weight are
+// given a weight of 1. If fn returns an error, reading stops and the error is returned.
+func StreamEdges(r io.Reader, fn func(u, v int, w float64) error) error {
+	return scanEdgeList(r, 0, fn)
+}
+
+// scanEdgeList calls fn for each edge of the edge list read from r. Lines must hold the given number
+// of fields, or either two or three if fields is zero.
+func scanEdgeList(r io.Reader, fields int, fn func(u, v int, w float64) error) error {
+	sc := bufio.NewScanner(r)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+		f := strings.Fields(text)
+		switch {
+		case fields == 0 && (len(f) < 2 || len(f) > 3):
+			return fmt.Errorf("graph: edge list: line %d: expected 2 or 3 fields, found %d", line, len(f))
+		case fields != 0 && len(f) != fields:
+			return fmt.Errorf("graph: edge list: line %d: expected %d fields, found %d", line, fields, len(f))
+		}
+		var id [2]int
+		for i := range id {
+			var err error
+			id[i], err = strconv.Atoi(f[i])
+			if err != nil || id[i] < 0 {
+				return fmt.Errorf("graph: edge list: line %d: invalid node ID %q", line, f[i])
+			}
+		}
+		w := 1.
+		if len(f) == 3 {
+			var err error
+			w, err = strconv.ParseFloat(f[2], 64)
+			if err != nil {
+				return fmt.Errorf("graph: edge list: line %d: invalid weight %q", line, f[2])
+			}
+		}
+		if err := fn(id[0], id[1], w); err != nil {
+			return err
+		}
+	}
+
+	return sc.Err()
+}
+
+// WriteEdgeList writes the edges of the graph to w as a tab-delimited edge list in edge ID order,
+// one edge per line giving the IDs of the edge's tail and head and, if weighted is true, its weight.
+// Nodes without edges are not represented in the output.
+func (g *Undirected) WriteEdgeList(w io.Writer, weighted bool) error {
+	bw := bufio.NewWriter(w)
+	for _, e := range g.edges {
+		if e == nil {
+			continue
+		}
+		fmt.Fprintf(bw, "%d\t%d", e.Tail().ID(), e.Head().ID())
+		if weighted {
+			fmt.Fprintf(bw, "\t%s", strconv.FormatFloat(e.Weight(), 'g', -1, 64))
+		}
+		fmt.Fprint(bw, "\n")
+	}
+
+	return bw.Flush()
+}
@@ -0,0 +1,89 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"bytes"
+	"errors"
+	check "launchpad.net/gocheck"
+	"strings"
+)
+
+// Tests
+func (s *S) TestEdgeList(c *check.C) {
+	const weighted = "# a weighted graph\n0 1\t7\n0  2 9\n\n2\t0\t-1.5\n3 3 1\n"
+	g, err := ReadEdgeList(strings.NewReader(weighted), true)
+	c.Assert(err, check.IsNil)
+	c.Check(g.Order(), check.Equals, 4)
+	c.Check(g.Size(), check.Equals, 4)
+	cs, _ := g.ConnectingEdges(g.Node(0), g.Node(2))
+	c.Check(len(cs), check.Equals, 2)
+
+	var buf bytes.Buffer
+	c.Assert(g.WriteEdgeList(&buf, true), check.IsNil)
+	c.Check(buf.String(), check.Equals, "0\t1\t7\n0\t2\t9\n2\t0\t-1.5\n3\t3\t1\n")
+
+	g, err = ReadEdgeList(strings.NewReader("#\n0 1\n1 2\n"), false)
+	c.Assert(err, check.IsNil)
+	c.Check(g.Size(), check.Equals, 2)
+	c.Check(g.Edge(1).Weight(), check.Equals, 1.)
+	buf.Reset()
+	c.Assert(g.WriteEdgeList(&buf, false), check.IsNil)
+	c.Check(buf.String(), check.Equals, "0\t1\n1\t2\n")
+
+	_, err = ReadEdgeList(strings.NewReader("0 1\n1 x\n"), false)
+	c.Check(err, check.ErrorMatches, `graph: edge list: line 2: invalid node ID "x"`)
+	_, err = ReadEdgeList(strings.NewReader("0 1\n"), true)
+	c.Check(err, check.ErrorMatches, "graph: edge list: line 1: expected 3 fields, found 2")
+	_, err = ReadEdgeList(strings.NewReader("0 400000000\n"), false)
+	c.Check(err, check.ErrorMatches, "graph: edge list: node ID 400000000 exceeds MaxReadID")
+}
+
+func (s *S) TestStreamEdges(c *check.C) {
+	const list = "# mixed weights\n0 1\t7\n0  2\n\n2\t0\t-1.5\n3 3\n"
+	var (
+		n     int
+		total float64
+		ends  [][2]int
+	)
+	err := StreamEdges(strings.NewReader(list), func(u, v int, w float64) error {
+		n++
+		total += w
+		ends = append(ends, [2]int{u, v})
+		return nil
+	})
+	c.Assert(err, check.IsNil)
+	c.Check(n, check.Equals, 4)
+	c.Check(total, check.Equals, 7.5)
+	c.Check(ends, check.DeepEquals, [][2]int{{0, 1}, {0, 2}, {2, 0}, {3, 3}})
+
+	stop := errors.New("stop")
+	n = 0
+	err = StreamEdges(strings.NewReader(list), func(u, v int, w float64) error {
+		n++
+		if n == 2 {
+			return stop
+		}
+		return nil
+	})
+	c.Check(err, check.Equals, stop)
+	c.Check(n, check.Equals, 2)
+
+	err = StreamEdges(strings.NewReader("0 1\n1\n"), func(u, v int, w float64) error { return nil })
+	c.Check(err, check.ErrorMatches, "graph: edge list: line 2: expected 2 or 3 fields, found 1")
+	err = StreamEdges(strings.NewReader("0 1 x\n"), func(u, v int, w float64) error { return nil })
+	c.Check(err, check.ErrorMatches, `graph: edge list: line 1: invalid weight "x"`)
+}
@@ -0,0 +1,140 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"math"
+)
+
+// MaxFlow returns the value of a maximum flow from the node source to the node sink using the
+// Edmonds-Karp algorithm, treating edge weights as capacities. Capacities are expected to be
+// non-negative. The residual capacity of each edge, its capacity less the flow assigned to it, is
+// returned keyed by edge ID. Self-loops carry no flow. If either node does not exist in the graph
+// or source and sink are the same node, the flow is zero and residual is nil.
+func (g *Directed) MaxFlow(source, sink Node) (flow float64, residual map[int]float64) {
+	flow, f := g.edmondsKarp(source, sink)
+	if f == nil {
+		return 0, nil
+	}
+	residual = make(map[int]float64, len(g.compEdges))
+	for _, e := range g.compEdges {
+		residual[e.ID()] = e.Weight() - f[e.ID()]
+	}
+
+	return flow, residual
+}
+
+// MinCutFromFlow returns a minimum cut separating the node source from the node sink, treating edge
+// weights as capacities. A maximum flow is found with MaxFlow and the cut is the set of edges, in ID
+// order, leading from nodes reachable from source in the residual graph to nodes that are not. All
+// such edges are saturated, and value, the sum of their capacities, is equal to the maximum flow. If
+// either node does not exist in the graph or source and sink are the same node, cut is nil and value
+// is zero.
+func (g *Directed) MinCutFromFlow(source, sink Node) (cut []Edge, value float64) {
+	_, f := g.edmondsKarp(source, sink)
+	if f == nil {
+		return nil, 0
+	}
+	reach := g.augmentingPath(source, nil, f, make(map[int]Edge))
+	for _, e := range g.edges {
+		if e != nil && marked(e.Tail(), reach) && !marked(e.Head(), reach) {
+			cut = append(cut, e)
+			value += e.Weight()
+		}
+	}
+
+	return cut, value
+}
+
+// edmondsKarp returns the value of a maximum flow from source to sink and the flow assigned to
+// each edge, keyed by edge ID.
+func (g *Directed) edmondsKarp(source, sink Node) (flow float64, f map[int]float64) {
+	if ok, _ := g.Has(source); !ok {
+		return 0, nil
+	}
+	if ok, _ := g.Has(sink); !ok || source == sink {
+		return 0, nil
+	}
+
+	f = make(map[int]float64)
+	pred := make(map[int]Edge)
+	for {
+		for k := range pred {
+			delete(pred, k)
+		}
+		if !marked(sink, g.augmentingPath(source, sink, f, pred)) {
+			return flow, f
+		}
+
+		// Find the bottleneck capacity along the path and push it through.
+		b := math.Inf(1)
+		for n := sink; n != source; {
+			e := pred[n.ID()]
+			if e.Head() == n {
+				b = math.Min(b, e.Weight()-f[e.ID()])
+			} else {
+				b = math.Min(b, f[e.ID()])
+			}
+			n = adjacent(e, n)
+		}
+		for n := sink; n != source; {
+			e := pred[n.ID()]
+			if e.Head() == n {
+				f[e.ID()] += b
+			} else {
+				f[e.ID()] -= b
+			}
+			n = adjacent(e, n)
+		}
+		flow += b
+	}
+}
+
+// augmentingPath performs a breadth-first search of the residual graph described by the edge flows
+// in f, recording the edge used to reach each node in pred. The search stops when sink is reached.
+// The nodes visited by the search are returned.
+func (g *Directed) augmentingPath(source, sink Node, f map[int]float64, pred map[int]Edge) (visits []bool) {
+	visits = mark(source, nil)
+	q := &queue{}
+	q.Enqueue(source)
+	for q.Len() > 0 {
+		u, _ := q.Dequeue()
+		for _, e := range u.Edges() {
+			var v Node
+			switch {
+			case e.Head() == e.Tail():
+				continue
+			case e.Tail() == u && e.Weight()-f[e.ID()] > 0:
+				v = e.Head()
+			case e.Head() == u && f[e.ID()] > 0:
+				v = e.Tail()
+			default:
+				continue
+			}
+			if marked(v, visits) {
+				continue
+			}
+			visits = mark(v, visits)
+			pred[v.ID()] = e
+			if v == sink {
+				return visits
+			}
+			q.Enqueue(v)
+		}
+	}
+
+	return visits
+}
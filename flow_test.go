@@ -0,0 +1,101 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+	"math/rand"
+)
+
+// Tests
+var flowNetwork = []we{
+	{0, 1, 16},
+	{0, 2, 13},
+	{1, 3, 12},
+	{2, 1, 4},
+	{2, 4, 14},
+	{3, 2, 9},
+	{3, 5, 20},
+	{4, 3, 7},
+	{4, 5, 4},
+}
+
+func weightedDirected(c *check.C, edges []we) (g *Directed) {
+	g = NewDirected()
+	for _, e := range edges {
+		u, _ := g.AddID(e.u)
+		v, _ := g.AddID(e.v)
+		g.Connect(u, v, e.w, 0)
+	}
+
+	return
+}
+
+func (s *S) TestMaxFlow(c *check.C) {
+	g := weightedDirected(c, flowNetwork)
+	flow, residual := g.MaxFlow(g.Node(0), g.Node(5))
+	c.Check(flow, check.Equals, 23.)
+	c.Check(residual, check.HasLen, g.Size())
+
+	// Flow is conserved at every node other than the source and sink.
+	net := make(map[int]float64)
+	for _, e := range g.Edges() {
+		r := residual[e.ID()]
+		c.Check(r >= 0 && r <= e.Weight(), check.Equals, true)
+		net[e.Tail().ID()] -= e.Weight() - r
+		net[e.Head().ID()] += e.Weight() - r
+	}
+	c.Check(net, check.DeepEquals, map[int]float64{0: -23, 1: 0, 2: 0, 3: 0, 4: 0, 5: 23})
+
+	flow, residual = g.MaxFlow(g.Node(5), g.Node(0))
+	c.Check(flow, check.Equals, 0.)
+	c.Check(residual[0], check.Equals, 16.)
+	flow, residual = g.MaxFlow(g.Node(0), g.Node(0))
+	c.Check(flow, check.Equals, 0.)
+	c.Check(residual, check.IsNil)
+}
+
+func (s *S) TestMinCutFromFlow(c *check.C) {
+	g := weightedDirected(c, flowNetwork)
+	cut, value := g.MinCutFromFlow(g.Node(0), g.Node(5))
+	c.Check(value, check.Equals, 23.)
+	var ids []int
+	for _, e := range cut {
+		ids = append(ids, e.ID())
+	}
+	c.Check(ids, check.DeepEquals, []int{2, 7, 8})
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		g := NewDirected()
+		for j := 0; j < 15; j++ {
+			g.AddID(j)
+		}
+		for j := 0; j < 50; j++ {
+			g.ConnectByID(rnd.Intn(15), rnd.Intn(15), float64(rnd.Intn(10)), 0)
+		}
+		flow, _ := g.MaxFlow(g.Node(0), g.Node(14))
+		cut, value := g.MinCutFromFlow(g.Node(0), g.Node(14))
+		c.Check(value, check.Equals, flow)
+
+		// Removing the cut leaves no flow from the source to the sink.
+		for _, e := range cut {
+			e.SetWeight(0)
+		}
+		flow, _ = g.MaxFlow(g.Node(0), g.Node(14))
+		c.Check(flow, check.Equals, 0.)
+	}
+}
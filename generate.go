@@ -0,0 +1,82 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"math/rand"
+)
+
+// GNP returns a new Erdős–Rényi random graph with n nodes, with IDs 0 to n-1, in which each pair of
+// distinct nodes is joined by an edge of weight 1 independently with probability p. Random numbers
+// are taken from src.
+func GNP(n int, p float64, src rand.Source) *Undirected {
+	rnd := rand.New(src)
+	g := NewUndirected()
+	for i := 0; i < n; i++ {
+		g.AddID(i)
+	}
+	for u := 0; u < n; u++ {
+		for v := u + 1; v < n; v++ {
+			if rnd.Float64() < p {
+				g.ConnectByID(u, v, 1, 0)
+			}
+		}
+	}
+
+	return g
+}
+
+// BarabasiAlbert returns a new Barabási–Albert preferential attachment random graph with n nodes,
+// with IDs 0 to n-1. Node m is joined to each of the first m nodes, and each later node is joined to m
+// distinct earlier nodes chosen with probability proportional to their degree, giving a graph with
+// m*(n-m) edges of weight 1. Random numbers are taken from src. BarabasiAlbert panics if m is not in
+// [1, n).
+func BarabasiAlbert(n, m int, src rand.Source) *Undirected {
+	if m < 1 || m >= n {
+		panic("graph: invalid number of edges per node")
+	}
+	rnd := rand.New(src)
+	g := NewUndirected()
+	for i := 0; i < n; i++ {
+		g.AddID(i)
+	}
+
+	sel := make(Selector, n)
+	for i := range sel {
+		sel[i].Index = i
+	}
+	for v := 0; v < m; v++ {
+		g.ConnectByID(m, v, 1, 0)
+		sel[v].Weight = 1
+	}
+	sel[m].Weight = float64(m)
+	sel.Init()
+
+	targets := make([]int, m)
+	for u := m + 1; u < n; u++ {
+		for i := range targets {
+			// Selection zeroes the weight of each target, so targets are distinct.
+			targets[i], _ = sel.SelectWith(rnd)
+		}
+		for _, v := range targets {
+			g.ConnectByID(u, v, 1, 0)
+			sel.Weight(v, float64(g.Node(v).Degree()))
+		}
+		sel.Weight(u, float64(m))
+	}
+
+	return g
+}
@@ -0,0 +1,57 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+	"math/rand"
+)
+
+// Tests
+func (s *S) TestGNP(c *check.C) {
+	const n = 100
+	g := GNP(n, 0, rand.NewSource(1))
+	c.Check(g.Order(), check.Equals, n)
+	c.Check(g.Size(), check.Equals, 0)
+
+	g = GNP(n, 1, rand.NewSource(1))
+	c.Check(g.Order(), check.Equals, n)
+	c.Check(g.Size(), check.Equals, n*(n-1)/2)
+
+	// The expected size is 2475 with a standard deviation of about 35.
+	g = GNP(n, 0.5, rand.NewSource(1))
+	c.Check(g.Order(), check.Equals, n)
+	c.Check(g.Size() > 2275 && g.Size() < 2675, check.Equals, true, check.Commentf("size = %d", g.Size()))
+	c.Check(g.SelfLoops(), check.HasLen, 0)
+}
+
+func (s *S) TestBarabasiAlbert(c *check.C) {
+	const n, m = 100, 3
+	g := BarabasiAlbert(n, m, rand.NewSource(1))
+	c.Check(g.Order(), check.Equals, n)
+	c.Check(g.Size(), check.Equals, m*(n-m))
+	c.Check(g.SelfLoops(), check.HasLen, 0)
+	c.Check(g.Simplify(nil, true).Size(), check.Equals, g.Size())
+	c.Check(g.IsConnected(all), check.Equals, true)
+	for _, n := range g.Nodes() {
+		c.Check(n.Degree() >= 1, check.Equals, true)
+	}
+	for _, n := range g.Nodes()[m:] {
+		c.Check(n.Degree() >= m, check.Equals, true)
+	}
+
+	c.Check(func() { BarabasiAlbert(3, 3, rand.NewSource(1)) }, check.Panics, "graph: invalid number of edges per node")
+}
@@ -0,0 +1,115 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+)
+
+type gexf struct {
+	XMLName xml.Name  `xml:"gexf"`
+	NS      string    `xml:"xmlns,attr"`
+	Version string    `xml:"version,attr"`
+	Graph   gexfGraph `xml:"graph"`
+}
+
+type gexfGraph struct {
+	EdgeType   string         `xml:"defaultedgetype,attr"`
+	Attributes gexfAttributes `xml:"attributes"`
+	Nodes      []gexfNode     `xml:"nodes>node"`
+	Edges      []gexfEdge     `xml:"edges>edge"`
+}
+
+type gexfAttributes struct {
+	Class     string          `xml:"class,attr"`
+	Attribute []gexfAttribute `xml:"attribute"`
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfNode struct {
+	ID    int    `xml:"id,attr"`
+	Label string `xml:"label,attr"`
+}
+
+type gexfEdge struct {
+	ID        int            `xml:"id,attr"`
+	Source    int            `xml:"source,attr"`
+	Target    int            `xml:"target,attr"`
+	Weight    float64        `xml:"weight,attr"`
+	Label     string         `xml:"label,attr,omitempty"`
+	AttValues []gexfAttValue `xml:"attvalues>attvalue"`
+}
+
+type gexfAttValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+// WriteGEXF writes a GEXF 1.2 representation of the graph, as used by Gephi, to w. Nodes are written
+// in order of ID and are labelled by ID, followed by edges in order of ID with their source and target
+// node IDs, weight and, if the edge is labelled, label. Whether each edge has the EdgeCut flag set is
+// written as the boolean edge attribute "cut".
+func (g *Undirected) WriteGEXF(w io.Writer) error {
+	doc := gexf{
+		NS:      "http://www.gexf.net/1.2draft",
+		Version: "1.2",
+		Graph: gexfGraph{
+			EdgeType: "undirected",
+			Attributes: gexfAttributes{
+				Class:     "edge",
+				Attribute: []gexfAttribute{{ID: "cut", Title: "cut", Type: "boolean"}},
+			},
+			Nodes: make([]gexfNode, 0, len(g.compNodes)),
+			Edges: make([]gexfEdge, 0, len(g.compEdges)),
+		},
+	}
+	for _, n := range g.nodes {
+		if n != nil {
+			doc.Graph.Nodes = append(doc.Graph.Nodes, gexfNode{ID: n.ID(), Label: strconv.Itoa(n.ID())})
+		}
+	}
+	for _, e := range g.edges {
+		if e == nil {
+			continue
+		}
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID:        e.ID(),
+			Source:    e.Tail().ID(),
+			Target:    e.Head().ID(),
+			Weight:    e.Weight(),
+			Label:     e.Label(),
+			AttValues: []gexfAttValue{{For: "cut", Value: strconv.FormatBool(e.Flags()&EdgeCut != 0)}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "\t")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,53 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"bytes"
+	"encoding/xml"
+	check "launchpad.net/gocheck"
+)
+
+// Tests
+func (s *S) TestWriteGEXF(c *check.C) {
+	g := weightedUndirected(c, []we{{0, 1, 1}, {1, 2, 2.5}, {2, 0, -3}, {4, 4, 1}})
+	g.Edge(1).SetFlags(EdgeCut)
+	g.Edge(2).SetLabel("<back>")
+	var buf bytes.Buffer
+	c.Assert(g.WriteGEXF(&buf), check.IsNil)
+
+	var doc gexf
+	c.Assert(xml.Unmarshal(buf.Bytes(), &doc), check.IsNil)
+	c.Check(doc.Graph.EdgeType, check.Equals, "undirected")
+	c.Check(doc.Graph.Nodes, check.HasLen, g.Order())
+	c.Check(doc.Graph.Edges, check.HasLen, g.Size())
+	for i, n := range doc.Graph.Nodes {
+		c.Check(n.ID, check.Equals, []int{0, 1, 2, 4}[i])
+	}
+	for i, e := range doc.Graph.Edges {
+		ge := g.Edge(i)
+		c.Check(e.ID, check.Equals, ge.ID())
+		c.Check(e.Source, check.Equals, ge.Tail().ID())
+		c.Check(e.Target, check.Equals, ge.Head().ID())
+		c.Check(e.Weight, check.Equals, ge.Weight())
+		c.Check(e.Label, check.Equals, ge.Label())
+		cut := "false"
+		if i == 1 {
+			cut = "true"
+		}
+		c.Check(e.AttValues, check.DeepEquals, []gexfAttValue{{For: "cut", Value: cut}})
+	}
+}
@@ -0,0 +1,308 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+var gmlEscaper = strings.NewReplacer("&", "&amp;", `"`, "&quot;")
+
+// WriteGML writes a Graph Modelling Language representation of the graph to w. Nodes are written in
+// order of ID with their id, followed by edges in order of ID with their id, source and target node
+// IDs, weight as value and, if the edge is labelled, label.
+func (g *Undirected) WriteGML(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprint(bw, "graph [\n\tdirected 0\n")
+	for _, n := range g.nodes {
+		if n != nil {
+			fmt.Fprintf(bw, "\tnode [\n\t\tid %d\n\t]\n", n.ID())
+		}
+	}
+	for _, e := range g.edges {
+		if e == nil {
+			continue
+		}
+		fmt.Fprintf(bw, "\tedge [\n\t\tid %d\n\t\tsource %d\n\t\ttarget %d\n\t\tvalue %s\n",
+			e.ID(), e.Tail().ID(), e.Head().ID(), strconv.FormatFloat(e.Weight(), 'g', -1, 64))
+		if l := e.Label(); l != "" {
+			fmt.Fprintf(bw, "\t\tlabel \"%s\"\n", gmlEscaper.Replace(l))
+		}
+		fmt.Fprint(bw, "\t]\n")
+	}
+	fmt.Fprint(bw, "]\n")
+
+	return bw.Flush()
+}
+
+// ReadGML reads an undirected Graph Modelling Language graph from r and returns the graph it
+// describes. Node id values are used as node IDs. Edge source and target values give the IDs of the
+// tail and head nodes of the edge, value gives its weight, which is 1 if absent, and label its label.
+// Edge id values are used as edge IDs where present, and other edges are given unused IDs. Nodes
+// referred to by an edge but not listed are added to the graph. Other keys are ignored.
+func ReadGML(r io.Reader) (*Undirected, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	lex := &gmlLexer{data: []rune(string(b)), line: 1}
+	top, err := lex.list(false)
+	if err != nil {
+		return nil, err
+	}
+
+	var graph *gmlPair
+	for i, p := range top {
+		if p.key == "graph" && p.list != nil {
+			graph = &top[i]
+			break
+		}
+	}
+	if graph == nil {
+		return nil, fmt.Errorf("graph: gml: no graph found")
+	}
+
+	g := NewUndirected()
+	var edges, noID []gmlEdge
+	for _, p := range graph.list {
+		switch p.key {
+		case "directed":
+			if p.text != "0" {
+				return nil, p.errorf("directed graphs are not supported")
+			}
+		case "node":
+			id, err := p.intValue("id", -1)
+			if err != nil {
+				return nil, err
+			}
+			if id < 0 || id > MaxReadID {
+				return nil, p.errorf("invalid node ID %d", id)
+			}
+			if _, err := g.AddID(id); err != nil {
+				return nil, p.errorf("duplicate node ID %d", id)
+			}
+		case "edge":
+			e := gmlEdge{gmlPair: p, w: 1}
+			if e.id, err = p.intValue("id", -1); err != nil {
+				return nil, err
+			}
+			if e.u, err = p.intValue("source", -1); err != nil {
+				return nil, err
+			}
+			if e.v, err = p.intValue("target", -1); err != nil {
+				return nil, err
+			}
+			if e.u < 0 || e.v < 0 || e.u > MaxReadID || e.v > MaxReadID {
+				return nil, p.errorf("missing or invalid edge end")
+			}
+			if e.id > MaxReadID {
+				return nil, p.errorf("invalid edge ID %d", e.id)
+			}
+			for _, f := range p.list {
+				switch f.key {
+				case "value":
+					if e.w, err = strconv.ParseFloat(f.text, 64); err != nil || f.list != nil {
+						return nil, f.errorf("invalid value %q", f.text)
+					}
+				case "label":
+					e.label = html.UnescapeString(f.text)
+				}
+			}
+			if e.id < 0 {
+				noID = append(noID, e)
+			} else {
+				edges = append(edges, e)
+			}
+		}
+	}
+
+	for _, e := range append(edges, noID...) {
+		u, err := g.AddID(e.u)
+		if err != nil && err != NodeExists {
+			return nil, err
+		}
+		v, err := g.AddID(e.v)
+		if err != nil && err != NodeExists {
+			return nil, err
+		}
+		var ne Edge
+		if e.id < 0 {
+			ne = g.newEdge(u, v, e.w, 0, e.label)
+		} else {
+			if e.id < len(g.edges) && g.edges[e.id] != nil {
+				return nil, e.errorf("duplicate edge ID %d", e.id)
+			}
+			ne = g.newEdgeKeepID(e.id, u, v, e.w, 0, e.label)
+		}
+		u.add(ne)
+		if v != u {
+			v.add(ne)
+		}
+	}
+
+	return g, nil
+}
+
+type gmlEdge struct {
+	gmlPair
+	id, u, v int
+	w        float64
+	label    string
+}
+
+// gmlPair is a GML key-value pair. The value is held in text unless it is a list.
+type gmlPair struct {
+	key  string
+	text string
+	list []gmlPair
+	line int
+}
+
+func (p gmlPair) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("graph: gml: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// intValue returns the integer value of the first pair in the list p with the given key, or def if there
+// is no such pair.
+func (p gmlPair) intValue(key string, def int) (int, error) {
+	for _, f := range p.list {
+		if f.key != key {
+			continue
+		}
+		i, err := strconv.Atoi(f.text)
+		if err != nil || f.list != nil {
+			return 0, f.errorf("invalid %s %q", key, f.text)
+		}
+		return i, nil
+	}
+	return def, nil
+}
+
+type gmlLexer struct {
+	data []rune
+	pos  int
+	line int
+}
+
+func (l *gmlLexer) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("graph: gml: line %d: %s", l.line, fmt.Sprintf(format, args...))
+}
+
+// list reads key-value pairs up to the end of the input or, if nested is true, the closing bracket
+// of the list.
+func (l *gmlLexer) list(nested bool) ([]gmlPair, error) {
+	var list []gmlPair
+	for {
+		l.skip()
+		if l.pos == len(l.data) {
+			if nested {
+				return nil, l.errorf("unexpected end of input")
+			}
+			return list, nil
+		}
+		r := l.data[l.pos]
+		if r == ']' {
+			if !nested {
+				return nil, l.errorf("unexpected %q", r)
+			}
+			l.pos++
+			return list, nil
+		}
+		if !unicode.IsLetter(r) && r != '_' {
+			return nil, l.errorf("expected key, found %q", r)
+		}
+		p := gmlPair{key: l.word(), line: l.line}
+
+		l.skip()
+		if l.pos == len(l.data) {
+			return nil, l.errorf("unexpected end of input")
+		}
+		switch r := l.data[l.pos]; {
+		case r == '[':
+			l.pos++
+			sub, err := l.list(true)
+			if err != nil {
+				return nil, err
+			}
+			if sub == nil {
+				sub = []gmlPair{}
+			}
+			p.list = sub
+		case r == '"':
+			start := l.line
+			end := l.pos + 1
+			for end < len(l.data) && l.data[end] != '"' {
+				if l.data[end] == '\n' {
+					l.line++
+				}
+				end++
+			}
+			if end == len(l.data) {
+				return nil, fmt.Errorf("graph: gml: line %d: unterminated string", start)
+			}
+			p.text = string(l.data[l.pos+1 : end])
+			l.pos = end + 1
+		case r == '-' || r == '+' || r == '.' || unicode.IsDigit(r):
+			p.text = l.word()
+		default:
+			return nil, l.errorf("unexpected %q", r)
+		}
+		list = append(list, p)
+	}
+}
+
+// word reads a key or number.
+func (l *gmlLexer) word() string {
+	start := l.pos
+	for l.pos++; l.pos < len(l.data); l.pos++ {
+		r := l.data[l.pos]
+		if r != '_' && r != '.' && r != '-' && r != '+' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			break
+		}
+	}
+	return string(l.data[start:l.pos])
+}
+
+// skip advances the lexer past white space and comment lines.
+func (l *gmlLexer) skip() {
+	bol := l.pos == 0
+	for l.pos < len(l.data) {
+		r := l.data[l.pos]
+		switch {
+		case r == '\n':
+			l.line++
+			l.pos++
+			bol = true
+			continue
+		case unicode.IsSpace(r):
+			l.pos++
+			continue
+		case r == '#' && bol:
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' {
+				l.pos++
+			}
+			continue
+		}
+		return
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"bytes"
+	check "launchpad.net/gocheck"
+	"strings"
+)
+
+// Tests
+var gmlUndirected = `graph [
+	directed 0
+	node [
+		id 0
+	]
+	node [
+		id 1
+	]
+	node [
+		id 2
+	]
+	node [
+		id 4
+	]
+	edge [
+		id 0
+		source 0
+		target 1
+		value 1
+	]
+	edge [
+		id 1
+		source 1
+		target 2
+		value 2.5
+		label "a &quot;b&quot; &amp; c"
+	]
+	edge [
+		id 2
+		source 2
+		target 0
+		value -3
+	]
+	edge [
+		id 3
+		source 4
+		target 4
+		value 1
+	]
+]
+`
+
+func (s *S) TestWriteGML(c *check.C) {
+	g := weightedUndirected(c, []we{{0, 1, 1}, {1, 2, 2.5}, {2, 0, -3}, {4, 4, 1}})
+	g.Edge(1).SetLabel(`a "b" & c`)
+	var buf bytes.Buffer
+	c.Assert(g.WriteGML(&buf), check.IsNil)
+	c.Check(buf.String(), check.Equals, gmlUndirected)
+}
+
+func (s *S) TestReadGML(c *check.C) {
+	g, err := ReadGML(strings.NewReader(gmlUndirected))
+	c.Assert(err, check.IsNil)
+	c.Check(g.Order(), check.Equals, 4)
+	c.Check(g.Size(), check.Equals, 4)
+	c.Check(g.Edge(1).Label(), check.Equals, `a "b" & c`)
+	var buf bytes.Buffer
+	c.Assert(g.WriteGML(&buf), check.IsNil)
+	c.Check(buf.String(), check.Equals, gmlUndirected)
+
+	g, err = ReadGML(strings.NewReader(`# written by hand
+Creator "igraph"
+graph [
+	label "a chain"
+	node [ id 3 label "x" graphics [ x 1.0 y 2.0 ] ]
+	node [ id 5 ]
+	edge [ source 3 target 5 value 2 ]
+	edge [ source 5 target 7 ]
+	edge [ id 0 source 7 target 3 ]
+]`))
+	c.Assert(err, check.IsNil)
+	c.Check(g.Order(), check.Equals, 3)
+	c.Check(g.Size(), check.Equals, 3)
+	c.Check(pathNodes(g.Node(7), []Edge{g.Edge(0), g.Edge(1), g.Edge(2)}), check.DeepEquals, []int{7, 3, 5, 7})
+	c.Check(g.Edge(1).Weight(), check.Equals, 2.)
+	c.Check(g.Edge(2).Weight(), check.Equals, 1.)
+
+	for _, t := range []struct {
+		gml string
+		err string
+	}{
+		{"graph [\n\tdirected 1\n]", "graph: gml: line 2: directed graphs are not supported"},
+		{"graph [\n\tedge [ source 0 target 1 value \"x\" ]\n]", `graph: gml: line 2: invalid value "x"`},
+		{"graph [\n\tedge [ target 1 ]\n]", "graph: gml: line 2: missing or invalid edge end"},
+		{"graph [\n\tnode [ id 1 ]\n\tnode [ id 1 ]\n]", "graph: gml: line 3: duplicate node ID 1"},
+		{"graph [\n\tnode [ id 400000000 ]\n]", "graph: gml: line 2: invalid node ID 400000000"},
+		{"graph [\n\tedge [ source 0 target 400000000 ]\n]", "graph: gml: line 2: missing or invalid edge end"},
+		{"graph [\n\tedge [ id 400000000 source 0 target 1 ]\n]", "graph: gml: line 2: invalid edge ID 400000000"},
+		{"graph [\n\tnode [ id 1 ]", "graph: gml: line 2: unexpected end of input"},
+		{"node [ id 1 ]", "graph: gml: no graph found"},
+	} {
+		_, err = ReadGML(strings.NewReader(t.gml))
+		c.Check(err, check.ErrorMatches, t.err)
+	}
+}
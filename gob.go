@@ -0,0 +1,40 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// GobEncode returns a gob encoding of the graph holding the same description of nodes and edges as
+// MarshalJSON: node IDs, and the ID, head and tail node IDs, weight, flags and label of each edge.
+func (g *Undirected) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(g.toJSONGraph())
+	return buf.Bytes(), err
+}
+
+// GobDecode replaces the contents of the graph with the graph described by the gob encoding in data,
+// as produced by GobEncode. Node and edge IDs are preserved and edges are reconnected to their nodes
+// by ID. IDs greater than MaxReadID are rejected.
+func (g *Undirected) GobDecode(data []byte) error {
+	var jg jsonGraph
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&jg); err != nil {
+		return err
+	}
+	return g.fromJSONGraph(jg)
+}
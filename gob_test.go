@@ -0,0 +1,64 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"bytes"
+	"encoding/gob"
+	check "launchpad.net/gocheck"
+)
+
+// Tests
+func (s *S) TestGob(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	g.AddID(8)
+	g.ConnectByID(8, 8, 0.5, 0)
+	g.Edge(3).SetFlags(EdgeCut)
+	g.Edge(6).SetLabel("likes")
+	g.DeleteByID(3)
+
+	var buf bytes.Buffer
+	c.Assert(gob.NewEncoder(&buf).Encode(g), check.IsNil)
+	r := NewUndirected()
+	c.Assert(gob.NewDecoder(&buf).Decode(r), check.IsNil)
+
+	c.Check(r.Equal(g), check.Equals, true)
+	c.Check(r.NextNodeID(), check.Equals, g.NextNodeID())
+	c.Check(r.NextEdgeID(), check.Equals, g.NextEdgeID())
+	for _, e := range g.Edges() {
+		re := r.Edge(e.ID())
+		c.Assert(re, check.NotNil)
+		c.Check(re.Tail(), check.Equals, r.Node(e.Tail().ID()))
+		c.Check(re.Head(), check.Equals, r.Node(e.Head().ID()))
+		c.Check(re.Weight(), check.Equals, e.Weight())
+		c.Check(re.Flags(), check.Equals, e.Flags())
+		c.Check(re.Label(), check.Equals, e.Label())
+	}
+	for _, n := range g.Nodes() {
+		c.Check(r.Node(n.ID()).Degree(), check.Equals, n.Degree())
+	}
+
+	sparse := NewUndirected()
+	sparse.AddID(2000)
+	sparse.ConnectByID(2000, 2000, 1, 0)
+	buf.Reset()
+	c.Assert(gob.NewEncoder(&buf).Encode(sparse), check.IsNil)
+	c.Assert(gob.NewDecoder(&buf).Decode(r), check.IsNil)
+	c.Check(r.Equal(sparse), check.Equals, true)
+	c.Check(r.Node(2000).Degree(), check.Equals, 2)
+
+	c.Check(r.GobDecode([]byte("not a gob")), check.NotNil)
+}
@@ -0,0 +1,113 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type jsonGraph struct {
+	Nodes []int      `json:"nodes"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+type jsonEdge struct {
+	ID     int       `json:"id"`
+	Head   int       `json:"head"`
+	Tail   int       `json:"tail"`
+	Weight float64   `json:"weight"`
+	Flags  EdgeFlags `json:"flags"`
+	Label  string    `json:"label,omitempty"`
+}
+
+// MarshalJSON returns a JSON encoding of the graph as an object holding a list of node IDs, nodes,
+// and a list of edges, each with its ID, head and tail node IDs, weight, flags and, if it is labelled,
+// label. Nodes and edges are listed in ID order.
+func (g *Undirected) MarshalJSON() ([]byte, error) {
+	return json.Marshal(g.toJSONGraph())
+}
+
+// UnmarshalJSON replaces the contents of the graph with the graph described by the JSON encoding in
+// data, as produced by MarshalJSON. Node and edge IDs are preserved. Nodes referred to by an edge but
+// not listed in nodes are added to the graph. IDs greater than MaxReadID are rejected.
+func (g *Undirected) UnmarshalJSON(data []byte) error {
+	var jg jsonGraph
+	if err := json.Unmarshal(data, &jg); err != nil {
+		return err
+	}
+	return g.fromJSONGraph(jg)
+}
+
+// toJSONGraph returns the serializable description of the graph used by MarshalJSON and GobEncode.
+func (g *Undirected) toJSONGraph() jsonGraph {
+	jg := jsonGraph{Nodes: []int{}, Edges: []jsonEdge{}}
+	for _, n := range g.nodes {
+		if n != nil {
+			jg.Nodes = append(jg.Nodes, n.ID())
+		}
+	}
+	for _, e := range g.edges {
+		if e != nil {
+			jg.Edges = append(jg.Edges, jsonEdge{
+				ID:     e.ID(),
+				Head:   e.Head().ID(),
+				Tail:   e.Tail().ID(),
+				Weight: e.Weight(),
+				Flags:  e.Flags(),
+				Label:  e.Label(),
+			})
+		}
+	}
+	return jg
+}
+
+// fromJSONGraph replaces the contents of the graph with the graph described by jg, reconnecting edges
+// to their nodes by ID.
+func (g *Undirected) fromJSONGraph(jg jsonGraph) error {
+	ng := NewUndirected()
+	for _, id := range jg.Nodes {
+		if id < 0 || id > MaxReadID {
+			return fmt.Errorf("graph: invalid node ID %d", id)
+		}
+		if _, err := ng.AddID(id); err != nil {
+			return err
+		}
+	}
+	for _, je := range jg.Edges {
+		if je.ID < 0 || je.ID > MaxReadID || (je.ID < len(ng.edges) && ng.edges[je.ID] != nil) {
+			return fmt.Errorf("graph: invalid edge ID %d", je.ID)
+		}
+		var ends [2]Node
+		for i, id := range [2]int{je.Tail, je.Head} {
+			if id < 0 || id > MaxReadID {
+				return fmt.Errorf("graph: invalid node ID %d", id)
+			}
+			ends[i], _ = ng.AddID(id)
+		}
+		u, v := ends[0], ends[1]
+		e := ng.newEdgeKeepID(je.ID, u, v, je.Weight, je.Flags, je.Label)
+		u.add(e)
+		if v != u {
+			v.add(e)
+		}
+	}
+	ng.stats = g.stats
+	ng.invalidate()
+	*g = *ng
+
+	return nil
+}
@@ -0,0 +1,74 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"encoding/json"
+	check "launchpad.net/gocheck"
+)
+
+// Tests
+func (s *S) TestJSON(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	g.AddID(8)
+	g.Edge(3).SetFlags(EdgeCut)
+	g.Edge(6).SetLabel("likes")
+	g.DeleteByID(3)
+	b, err := json.Marshal(g)
+	c.Assert(err, check.IsNil)
+
+	r := NewUndirected()
+	c.Assert(json.Unmarshal(b, r), check.IsNil)
+	c.Check(r.Order(), check.Equals, g.Order())
+	c.Check(r.Size(), check.Equals, g.Size())
+	c.Check(r.NextNodeID(), check.Equals, g.NextNodeID())
+	for _, e := range g.Edges() {
+		re := r.Edge(e.ID())
+		c.Assert(re, check.NotNil)
+		c.Check(re.Weight(), check.Equals, e.Weight())
+		c.Check(re.Flags(), check.Equals, e.Flags())
+		c.Check(re.Label(), check.Equals, e.Label())
+		c.Check(re.Tail().ID(), check.Equals, e.Tail().ID())
+		c.Check(re.Head().ID(), check.Equals, e.Head().ID())
+	}
+	ok, _ := r.HasNodeID(3)
+	c.Check(ok, check.Equals, false)
+
+	c.Assert(json.Unmarshal([]byte(`{"nodes":[0],"edges":[{"id":2,"tail":0,"head":4,"weight":1}]}`), r), check.IsNil)
+	c.Check(r.Order(), check.Equals, 2)
+	c.Check(r.Edge(2).Head(), check.Equals, r.Node(4))
+	c.Check(r.Node(4).Degree(), check.Equals, 1)
+
+	for _, t := range []struct {
+		json string
+		err  string
+	}{
+		{`{"nodes":[400000000]}`, "graph: invalid node ID 400000000"},
+		{`{"nodes":[0],"edges":[{"id":400000000,"tail":0,"head":0}]}`, "graph: invalid edge ID 400000000"},
+		{`{"nodes":[0],"edges":[{"id":0,"tail":0,"head":9007199254740991}]}`, "graph: invalid node ID 9007199254740991"},
+	} {
+		c.Check(json.Unmarshal([]byte(t.json), r), check.ErrorMatches, t.err)
+	}
+
+	sparse := NewUndirected()
+	sparse.AddID(2000)
+	b, err = json.Marshal(sparse)
+	c.Assert(err, check.IsNil)
+	c.Check(string(b), check.Equals, `{"nodes":[2000],"edges":[]}`)
+	c.Assert(json.Unmarshal(b, r), check.IsNil)
+	c.Check(r.Equal(sparse), check.Equals, true)
+	c.Check(r.NextNodeID(), check.Equals, 2001)
+}
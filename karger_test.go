@@ -16,11 +16,13 @@
 package graph
 
 import (
+	"context"
 	check "launchpad.net/gocheck"
 	"math"
 	"math/rand"
 	"runtime"
 	"testing"
+	"time"
 )
 
 type N struct {
@@ -232,3 +234,162 @@ func BenchmarkParFastKarger(b *testing.B) {
 		ParFastRandMinCut(G, lo*lo, runtime.GOMAXPROCS(0))
 	}
 }
+
+func (s *S) TestKargerSeeded(c *check.C) {
+	ids := func(es []Edge) []int {
+		var id []int
+		for _, e := range es {
+			id = append(id, e.ID())
+		}
+		return id
+	}
+	for j, g := range testG {
+		G := createGraph(g)
+		lo := int(math.Log(float64(G.Order())))
+		for _, f := range []func(int64) ([]Edge, float64){
+			func(seed int64) ([]Edge, float64) { return FastRandMinCutSeed(G, lo*lo, rand.NewSource(seed)) },
+			func(seed int64) ([]Edge, float64) {
+				return FastRandMinCutParSeed(G, lo*lo, runtime.GOMAXPROCS(0), rand.NewSource(seed))
+			},
+			func(seed int64) ([]Edge, float64) {
+				return ParFastRandMinCutSeed(G, lo*lo, runtime.GOMAXPROCS(0), rand.NewSource(seed))
+			},
+		} {
+			c0, w0 := f(1)
+			c1, w1 := f(1)
+			c.Check(w0, check.Equals, cutExpects[j])
+			c.Check(w1, check.Equals, w0)
+			c.Check(ids(c1), check.DeepEquals, ids(c0))
+		}
+	}
+
+	sel := make(Selector, 100)
+	for i := range sel {
+		sel[i] = WeightedItem{Index: i, Weight: 1}
+	}
+	draw := func(seed int64) []int {
+		sel.Init()
+		rnd := rand.New(rand.NewSource(seed))
+		var d []int
+		for {
+			i, err := sel.SelectWith(rnd)
+			if err != nil {
+				return d
+			}
+			d = append(d, i)
+		}
+	}
+	d := draw(1)
+	c.Check(len(d), check.Equals, len(sel))
+	for i := range sel {
+		sel[i].Weight = 1
+	}
+	c.Check(draw(1), check.DeepEquals, d)
+}
+
+func (s *S) TestKargerContext(c *check.C) {
+	G := createGraph(testG[0])
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ce, w, err := FastRandMinCutContext(ctx, G, 10)
+	c.Check(err, check.Equals, context.Canceled)
+	c.Check(ce, check.IsNil)
+	c.Check(math.IsInf(w, 1), check.Equals, true)
+
+	for _, f := range []func(context.Context) ([]Edge, float64, error){
+		func(ctx context.Context) ([]Edge, float64, error) {
+			return FastRandMinCutContext(ctx, G, math.MaxInt32)
+		},
+		func(ctx context.Context) ([]Edge, float64, error) {
+			return FastRandMinCutParContext(ctx, G, math.MaxInt32, runtime.GOMAXPROCS(0))
+		},
+		func(ctx context.Context) ([]Edge, float64, error) {
+			return ParFastRandMinCutContext(ctx, G, math.MaxInt32, runtime.GOMAXPROCS(0))
+		},
+	} {
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		start := time.Now()
+		ce, w, err := f(ctx)
+		cancel()
+		c.Check(err, check.Equals, context.DeadlineExceeded)
+		c.Check(time.Since(start) < 5*time.Second, check.Equals, true)
+		c.Check(len(ce) > 0, check.Equals, true)
+		c.Check(float64(len(ce)), check.Equals, w)
+	}
+
+	// Cancellation is seen within the recursion, not only between iterations.
+	k := newKargerRP(G)
+	k.ctx = ctx
+	k.split = runtime.GOMAXPROCS(0)
+	k.init()
+	k.fastRandMinCut()
+	c.Check(k.c, check.IsNil)
+	c.Check(math.IsInf(k.w, 1), check.Equals, true)
+}
+
+func (s *S) TestKargerApplyCut(c *check.C) {
+	uncut := func(e Edge) bool { return e.Flags()&EdgeCut == 0 }
+	for _, g := range testG {
+		G := createGraph(g)
+		lo := int(math.Log(float64(G.Order())))
+		cut, _ := FastRandMinCutSeed(G, lo*lo, rand.NewSource(1))
+		c.Check(G.ConnectedComponents(uncut), check.HasLen, 1)
+		G.ApplyCut(cut)
+		for _, e := range cut {
+			c.Check(e.Flags()&EdgeCut, check.Equals, EdgeCut)
+		}
+		c.Check(G.ConnectedComponents(uncut), check.HasLen, 2)
+		G.ClearCut()
+		for _, e := range G.Edges() {
+			c.Check(e.Flags()&EdgeCut, check.Equals, EdgeFlags(0))
+		}
+		c.Check(G.ConnectedComponents(uncut), check.HasLen, 1)
+	}
+}
+
+func (s *S) TestKargerMinEdges(c *check.C) {
+	rand.Seed(0)
+	// Node 0 is cut from a heavy clique by a single edge of weight 2, and node 5 by two
+	// parallel edges of weight 1.
+	g := weightedUndirected(c, []we{
+		{0, 1, 2},
+		{1, 2, 10}, {1, 3, 10}, {1, 4, 10}, {2, 3, 10}, {2, 4, 10}, {3, 4, 10},
+		{4, 5, 1}, {4, 5, 1},
+	})
+	cut, w := FastRandMinCutMinEdges(g, 50)
+	c.Check(w, check.Equals, 2.)
+	c.Assert(cut, check.HasLen, 1)
+	c.Check(cut[0].ID(), check.Equals, 0)
+}
+
+func (s *S) TestKargerPartition(c *check.C) {
+	checkPartition := func(G *Undirected, cut []Edge, a, b []Node, w float64) {
+		c.Check(len(a)+len(b), check.Equals, G.Order())
+		c.Check(len(a) > 0 && len(b) > 0, check.Equals, true)
+		c.Check(a[0], check.Equals, G.Node(1))
+		side := make(map[Node]bool)
+		for _, n := range a {
+			side[n] = true
+		}
+		for _, e := range cut {
+			c.Check(side[e.Head()] != side[e.Tail()], check.Equals, true)
+		}
+		for _, e := range G.Edges() {
+			if side[e.Head()] != side[e.Tail()] {
+				w--
+			}
+		}
+		c.Check(w, check.Equals, 0.)
+	}
+	for j, g := range testG {
+		G := createGraph(g)
+		lo := int(math.Log(float64(G.Order())))
+		cut, w := FastRandMinCutSeed(G, lo*lo, rand.NewSource(1))
+		c.Check(w, check.Equals, cutExpects[j])
+		a, b := cutPartition(G, cut)
+		checkPartition(G, cut, a, b, w)
+
+		cut, a, b, w = FastRandMinCutPartition(G, lo*lo)
+		checkPartition(G, cut, a, b, w)
+	}
+}
@@ -16,7 +16,9 @@
 package graph
 
 import (
+	"context"
 	"math"
+	"math/rand"
 	"runtime"
 	"sync"
 )
@@ -28,10 +30,34 @@ const sqrt2 = 1.4142135623730950488016887242096980785696718753769480
 var MaxProcs = runtime.GOMAXPROCS(0)
 
 func FastRandMinCut(g *Undirected, iter int) (c []Edge, w float64) {
+	c, w, _ = newKargerR(g).minCut(context.Background(), iter)
+	return
+}
+
+// FastRandMinCutSeed behaves as FastRandMinCut, but uses src as the source of random numbers so that
+// results are reproducible.
+func FastRandMinCutSeed(g *Undirected, iter int, src rand.Source) (c []Edge, w float64) {
 	ka := newKargerR(g)
-	ka.init()
+	ka.rnd = rand.New(src)
+	c, w, _ = ka.minCut(context.Background(), iter)
+	return
+}
+
+// FastRandMinCutContext behaves as FastRandMinCut, but checks for cancellation of ctx between
+// iterations. If ctx is cancelled, the best cut found so far is returned with the error from ctx.
+func FastRandMinCutContext(ctx context.Context, g *Undirected, iter int) ([]Edge, float64, error) {
+	return newKargerR(g).minCut(ctx, iter)
+}
+
+func (ka *kargerR) minCut(ctx context.Context, iter int) (c []Edge, w float64, err error) {
 	w = math.Inf(1)
 	for i := 0; i < iter; i++ {
+		select {
+		case <-ctx.Done():
+			return c, w, ctx.Err()
+		default:
+		}
+		ka.init()
 		ka.fastRandMinCut()
 		if ka.w < w {
 			w = ka.w
@@ -42,9 +68,89 @@ func FastRandMinCut(g *Undirected, iter int) (c []Edge, w float64) {
 	return
 }
 
+// FastRandMinCutPartition behaves as FastRandMinCut, but additionally returns the two sets of nodes
+// separated by the cut. partA holds the node with the lowest ID. If the graph is not connected, partB
+// holds all the nodes not in the same contracted set as that node.
+func FastRandMinCutPartition(g *Undirected, iter int) (cut []Edge, partA, partB []Node, w float64) {
+	cut, w = FastRandMinCut(g, iter)
+	partA, partB = cutPartition(g, cut)
+	return
+}
+
+// cutPartition returns the nodes of g reachable from the node with the lowest ID without crossing an
+// edge in cut, and the remaining nodes. For a cut found by contraction, these are the two contracted
+// sets, since each set is joined by the contracted edges and every edge between them is in the cut.
+func cutPartition(g *Undirected, cut []Edge) (a, b []Node) {
+	if len(g.compNodes) == 0 {
+		return nil, nil
+	}
+	inCut := make(map[Edge]struct{}, len(cut))
+	for _, e := range cut {
+		inCut[e] = struct{}{}
+	}
+	bf := NewBreadthFirstSize(len(g.nodes))
+	var s Node
+	for _, n := range g.nodes {
+		if n != nil {
+			s = n
+			break
+		}
+	}
+	bf.Search(s, func(e Edge) bool { _, ok := inCut[e]; return !ok }, func(Node) bool { return false }, nil)
+	for _, n := range g.nodes {
+		if n == nil {
+			continue
+		}
+		if bf.Visited(n) {
+			a = append(a, n)
+		} else {
+			b = append(b, n)
+		}
+	}
+
+	return
+}
+
+// FastRandMinCutMinEdges behaves as FastRandMinCut, but when more than one cut of least weight is
+// found over the iterations, the cut with the fewest edges is returned. Of those, the first found is
+// returned.
+func FastRandMinCutMinEdges(g *Undirected, iter int) (c []Edge, w float64) {
+	ka := newKargerR(g)
+	w = math.Inf(1)
+	for i := 0; i < iter; i++ {
+		ka.init()
+		ka.fastRandMinCut()
+		if ka.w < w || (ka.w == w && len(ka.c) < len(c)) {
+			w = ka.w
+			c = ka.c
+		}
+	}
+
+	return
+}
+
 // parallelised outside the recursion tree
 
 func FastRandMinCutPar(g *Undirected, iter, thread int) (c []Edge, w float64) {
+	c, w, _ = fastRandMinCutPar(context.Background(), g, iter, thread, nil)
+	return
+}
+
+// FastRandMinCutParSeed behaves as FastRandMinCutPar, but uses src to seed an independent source of
+// random numbers for each thread so that results are reproducible.
+func FastRandMinCutParSeed(g *Undirected, iter, thread int, src rand.Source) (c []Edge, w float64) {
+	c, w, _ = fastRandMinCutPar(context.Background(), g, iter, thread, src)
+	return
+}
+
+// FastRandMinCutParContext behaves as FastRandMinCutPar, but each thread checks for cancellation of
+// ctx between iterations. If ctx is cancelled, the best cut found so far is returned with the error
+// from ctx.
+func FastRandMinCutParContext(ctx context.Context, g *Undirected, iter, thread int) ([]Edge, float64, error) {
+	return fastRandMinCutPar(ctx, g, iter, thread, nil)
+}
+
+func fastRandMinCutPar(ctx context.Context, g *Undirected, iter, thread int, src rand.Source) (c []Edge, w float64, err error) {
 	if thread > MaxProcs {
 		thread = MaxProcs
 	}
@@ -54,11 +160,16 @@ func FastRandMinCutPar(g *Undirected, iter, thread int) (c []Edge, w float64) {
 	iter, rem := iter/thread+1, iter%thread
 
 	type r struct {
-		c []Edge
-		w float64
+		c   []Edge
+		w   float64
+		err error
 	}
 	rs := make([]*r, thread)
 
+	var rnd *rand.Rand
+	if src != nil {
+		rnd = rand.New(src)
+	}
 	wg := &sync.WaitGroup{}
 	for j := 0; j < thread; j++ {
 		if rem == 0 {
@@ -67,24 +178,15 @@ func FastRandMinCutPar(g *Undirected, iter, thread int) (c []Edge, w float64) {
 		if rem >= 0 {
 			rem--
 		}
+		ka := newKargerR(g)
+		if rnd != nil {
+			ka.rnd = rand.New(rand.NewSource(rnd.Int63()))
+		}
 		wg.Add(1)
 		go func(j, iter int) {
 			defer wg.Done()
-			ka := newKargerR(g)
-			ka.init()
-			var (
-				w = math.Inf(1)
-				c []Edge
-			)
-			for i := 0; i < iter; i++ {
-				ka.fastRandMinCut()
-				if ka.w < w {
-					w = ka.w
-					c = ka.c
-				}
-			}
-
-			rs[j] = &r{c, w}
+			c, w, err := ka.minCut(ctx, iter)
+			rs[j] = &r{c, w, err}
 		}(j, iter)
 	}
 
@@ -95,6 +197,9 @@ func FastRandMinCutPar(g *Undirected, iter, thread int) (c []Edge, w float64) {
 			w = subr.w
 			c = subr.c
 		}
+		if subr.err != nil {
+			err = subr.err
+		}
 	}
 
 	return
@@ -105,6 +210,7 @@ type kargerR struct {
 	order int
 	ind   []super
 	sel   Selector
+	rnd   *rand.Rand
 	c     []Edge
 	w     float64
 }
@@ -138,6 +244,7 @@ func (ka *kargerR) clone() (c *kargerR) {
 		g:     ka.g,
 		ind:   make([]super, ka.g.NextNodeID()),
 		sel:   make(Selector, ka.g.Size()),
+		rnd:   ka.rnd,
 		order: ka.order,
 	}
 
@@ -177,7 +284,7 @@ func (ka *kargerR) fastRandMinCut() {
 
 func (ka *kargerR) randContract(k int) {
 	for ka.order > k {
-		id, err := ka.sel.Select()
+		id, err := ka.sel.SelectWith(ka.rnd)
 		if err != nil {
 			break
 		}
@@ -213,7 +320,7 @@ func (ka *kargerR) randContract(k int) {
 
 func (ka *kargerR) randCompact(k int) {
 	for ka.order > k {
-		id, err := ka.sel.Select()
+		id, err := ka.sel.SelectWith(ka.rnd)
 		if err != nil {
 			break
 		}
@@ -263,14 +370,44 @@ func (ka *kargerR) loop(e Edge) bool {
 // parallelised within the recursion tree
 
 func ParFastRandMinCut(g *Undirected, iter, threads int) (c []Edge, w float64) {
+	c, w, _ = parFastRandMinCut(context.Background(), g, iter, threads, nil)
+	return
+}
+
+// ParFastRandMinCutSeed behaves as ParFastRandMinCut, but uses src as the source of random numbers,
+// deriving an independent source for each branch of the recursion tree, so that results are
+// reproducible.
+func ParFastRandMinCutSeed(g *Undirected, iter, threads int, src rand.Source) (c []Edge, w float64) {
+	c, w, _ = parFastRandMinCut(context.Background(), g, iter, threads, src)
+	return
+}
+
+// ParFastRandMinCutContext behaves as ParFastRandMinCut, but checks for cancellation of ctx between
+// iterations and at each step of the recursion, so branches running in their own goroutines are
+// abandoned once ctx is cancelled. If ctx is cancelled, the best cut found so far is returned with the
+// error from ctx.
+func ParFastRandMinCutContext(ctx context.Context, g *Undirected, iter, threads int) ([]Edge, float64, error) {
+	return parFastRandMinCut(ctx, g, iter, threads, nil)
+}
+
+func parFastRandMinCut(ctx context.Context, g *Undirected, iter, threads int, src rand.Source) (c []Edge, w float64, err error) {
 	k := newKargerRP(g)
+	k.ctx = ctx
+	if src != nil {
+		k.rnd = rand.New(src)
+	}
 	k.split = threads
 	if k.split == 0 {
 		k.split = -1
 	}
-	k.init()
 	w = math.Inf(1)
 	for i := 0; i < iter; i++ {
+		select {
+		case <-ctx.Done():
+			return c, w, ctx.Err()
+		default:
+		}
+		k.init()
 		k.fastRandMinCut()
 		if k.w < w {
 			w = k.w
@@ -278,14 +415,16 @@ func ParFastRandMinCut(g *Undirected, iter, threads int) (c []Edge, w float64) {
 		}
 	}
 
-	return
+	return c, w, ctx.Err()
 }
 
 type kargerRP struct {
+	ctx   context.Context
 	g     *Undirected
 	order int
 	ind   []super
 	sel   Selector
+	rnd   *rand.Rand
 	c     []Edge
 	w     float64
 	count int
@@ -294,6 +433,7 @@ type kargerRP struct {
 
 func newKargerRP(g *Undirected) *kargerRP {
 	return &kargerRP{
+		ctx: context.Background(),
 		g:   g,
 		ind: make([]super, g.NextNodeID()),
 		sel: make(Selector, g.Size()),
@@ -318,12 +458,16 @@ func (ka *kargerRP) init() {
 
 func (ka *kargerRP) clone() (c *kargerRP) {
 	c = &kargerRP{
+		ctx:   ka.ctx,
 		g:     ka.g,
 		ind:   make([]super, ka.g.NextNodeID()),
 		sel:   make(Selector, ka.g.Size()),
 		order: ka.order,
 		count: ka.count,
 	}
+	if ka.rnd != nil {
+		c.rnd = rand.New(rand.NewSource(ka.rnd.Int63()))
+	}
 
 	copy(c.sel, ka.sel)
 	for i, n := range ka.ind {
@@ -339,6 +483,11 @@ func (ka *kargerRP) clone() (c *kargerRP) {
 }
 
 func (ka *kargerRP) fastRandMinCut() {
+	if ka.ctx.Err() != nil {
+		// An abandoned branch must not be chosen over one that completed.
+		ka.c, ka.w = nil, math.Inf(1)
+		return
+	}
 	if ka.order <= 6 {
 		ka.randCompact(2)
 		return
@@ -381,7 +530,7 @@ func (ka *kargerRP) fastRandMinCut() {
 
 func (ka *kargerRP) randContract(k int) {
 	for ka.order > k {
-		id, err := ka.sel.Select()
+		id, err := ka.sel.SelectWith(ka.rnd)
 		if err != nil {
 			break
 		}
@@ -417,7 +566,7 @@ func (ka *kargerRP) randContract(k int) {
 
 func (ka *kargerRP) randCompact(k int) {
 	for ka.order > k {
-		id, err := ka.sel.Select()
+		id, err := ka.sel.SelectWith(ka.rnd)
 		if err != nil {
 			break
 		}
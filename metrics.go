@@ -0,0 +1,546 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Eccentricity returns the greatest shortest path distance from the node n to any other node in the
+// graph, traversing edges that satisfy the edge filter ef and using edge weights as distances. For a
+// graph with unit edge weights this is the hop count. If any node cannot be reached from n, +Inf is
+// returned; the eccentricity within n's connected component can be found by building a graph from the
+// component with Nodes.BuildUndirected. If n does not exist in the graph or a negative edge weight is
+// encountered, NaN is returned.
+func (g *Undirected) Eccentricity(n Node, ef EdgeFilter) float64 {
+	dist, _, err := g.ShortestPaths(n, ef)
+	if err != nil {
+		return math.NaN()
+	}
+	if len(dist) < g.Order() {
+		return math.Inf(1)
+	}
+	var ecc float64
+	for _, d := range dist {
+		if d > ecc {
+			ecc = d
+		}
+	}
+
+	return ecc
+}
+
+// Diameter returns the greatest shortest path distance between any pair of nodes in the graph and a
+// pair of nodes separated by that distance, traversing edges that satisfy the edge filter ef and using
+// edge weights as distances. For a graph with unit edge weights this is the hop count. If the graph is
+// not connected, +Inf is returned with a pair of nodes that cannot reach each other. If a negative edge
+// weight is encountered, NaN is returned. The diameter of an empty graph is zero.
+func (g *Undirected) Diameter(ef EdgeFilter) (d float64, endpoints [2]Node) {
+	for _, u := range g.compNodes {
+		dist, _, err := g.ShortestPaths(u, ef)
+		if err != nil {
+			return math.NaN(), [2]Node{}
+		}
+		if len(dist) < g.Order() {
+			for _, v := range g.compNodes {
+				if _, ok := dist[v.ID()]; !ok {
+					return math.Inf(1), [2]Node{u, v}
+				}
+			}
+		}
+		for id, du := range dist {
+			if du > d || endpoints[0] == nil {
+				d, endpoints = du, [2]Node{u, g.nodes[id]}
+			}
+		}
+	}
+
+	return d, endpoints
+}
+
+// Girth returns the length in edges of the shortest cycle in the graph, or -1 if the graph is
+// acyclic. A self-loop is a cycle of length 1 and a pair of parallel edges is a cycle of length 2.
+// The girth is found by a breadth-first search from each node, taking the shortest cycle closed by
+// an edge other than the one by which a node was reached.
+func (g *Undirected) Girth() int {
+	girth := -1
+	all := func(_ Edge) bool { return true }
+	dist := make([]int, len(g.nodes))
+	from := make([]Edge, len(g.nodes))
+	q := &queue{}
+	for _, r := range g.compNodes {
+		for i := range dist {
+			dist[i] = -1
+			from[i] = nil
+		}
+		dist[r.ID()] = 0
+		q.Enqueue(r)
+		for q.Len() > 0 {
+			u, _ := q.Dequeue()
+			du := dist[u.ID()]
+			if girth >= 0 && 2*du+1 >= girth {
+				// No shorter cycle can be closed from here.
+				q.Clear()
+				break
+			}
+			for _, h := range u.Hops(all) {
+				if h.Edge == from[u.ID()] {
+					continue
+				}
+				v := h.Node
+				if dv := dist[v.ID()]; dv >= 0 {
+					if l := du + dv + 1; girth < 0 || l < girth {
+						girth = l
+					}
+					continue
+				}
+				dist[v.ID()] = du + 1
+				from[v.ID()] = h.Edge
+				q.Enqueue(v)
+			}
+		}
+		if girth == 1 {
+			break
+		}
+	}
+
+	return girth
+}
+
+// ClusteringCoefficient returns the local clustering coefficient of the node n, the fraction of pairs
+// of distinct neighbors of n that are themselves adjacent. Multiply connected neighbors are counted
+// once and self-loops are ignored. Nodes with fewer than two neighbors have a coefficient of zero.
+func (g *Undirected) ClusteringCoefficient(n Node) float64 {
+	links, pairs := clustering(n)
+	if pairs == 0 {
+		return 0
+	}
+	return links / pairs
+}
+
+// JaccardSimilarity returns the Jaccard similarity of the nodes a and b, the number of nodes that are
+// neighbors of both a and b divided by the number that are neighbors of either. Neighbors are counted
+// once however many edges join them, and self-loops are ignored. Nodes with no common neighbors
+// score zero.
+func (g *Undirected) JaccardSimilarity(a, b Node) float64 {
+	all := func(_ Edge) bool { return true }
+	na, nb := distinctNeighbors(a, all), distinctNeighbors(b, all)
+	common := len(commonNeighbors(na, nb))
+	if common == 0 {
+		return 0
+	}
+	return float64(common) / float64(len(na)+len(nb)-common)
+}
+
+// WeightedNeighborSimilarity returns the weighted Jaccard similarity of the nodes a and b. For each
+// node z, the total weight of the edges joining a to z and of those joining b to z are found, ignoring
+// self-loops; the similarity is the sum over z of the smaller of the two totals divided by the sum of
+// the larger. Zero-weight edges therefore contribute nothing. Edge weights must not be negative. If
+// neither node has an edge of positive weight, zero is returned.
+func (g *Undirected) WeightedNeighborSimilarity(a, b Node) float64 {
+	wa, wb := neighborWeights(a), neighborWeights(b)
+	ids := make([]int, 0, len(wa)+len(wb))
+	for z := range wa {
+		ids = append(ids, z)
+	}
+	for z := range wb {
+		if _, ok := wa[z]; !ok {
+			ids = append(ids, z)
+		}
+	}
+	// Sum in a fixed order so that the result does not depend on map iteration.
+	sort.Ints(ids)
+	var min, max float64
+	for _, z := range ids {
+		min += math.Min(wa[z], wb[z])
+		max += math.Max(wa[z], wb[z])
+	}
+	if max == 0 {
+		return 0
+	}
+	return min / max
+}
+
+// neighborWeights returns the total weight of the edges joining n to each of its neighbors other
+// than itself, keyed by node ID.
+func neighborWeights(n Node) map[int]float64 {
+	w := make(map[int]float64)
+	for _, h := range n.Hops(func(_ Edge) bool { return true }) {
+		if h.Node != n {
+			w[h.Node.ID()] += h.Edge.Weight()
+		}
+	}
+	return w
+}
+
+// AdamicAdar returns the Adamic-Adar index of the nodes a and b, the sum over the nodes that are
+// neighbors of both a and b of the reciprocal of the logarithm of their number of neighbors.
+// Neighbors are counted once however many edges join them, and self-loops are ignored. Nodes with no
+// common neighbors score zero.
+func (g *Undirected) AdamicAdar(a, b Node) float64 {
+	all := func(_ Edge) bool { return true }
+	var aa float64
+	for _, z := range commonNeighbors(distinctNeighbors(a, all), distinctNeighbors(b, all)) {
+		// A common neighbor of a node with itself may have no other neighbor.
+		if k := len(distinctNeighbors(z, all)); k > 1 {
+			aa += 1 / math.Log(float64(k))
+		}
+	}
+	return aa
+}
+
+// commonNeighbors returns the nodes in both na and nb, in the order of na.
+func commonNeighbors(na, nb []Node) []Node {
+	set := make(map[Node]struct{}, len(nb))
+	for _, n := range nb {
+		set[n] = struct{}{}
+	}
+	var common []Node
+	for _, n := range na {
+		if _, ok := set[n]; ok {
+			common = append(common, n)
+		}
+	}
+	return common
+}
+
+// GlobalClusteringCoefficient returns the transitivity of the graph, the ratio of three times the
+// number of triangles to the number of connected triples of nodes. Multiply connected nodes are
+// counted as adjacent once and self-loops are ignored.
+func (g *Undirected) GlobalClusteringCoefficient() float64 {
+	var links, pairs float64
+	for _, n := range g.compNodes {
+		l, p := clustering(n)
+		links += l
+		pairs += p
+	}
+	if pairs == 0 {
+		return 0
+	}
+	return links / pairs
+}
+
+// Density returns the ratio of the number of edges in the graph to the number of edges in a complete
+// graph of the same order, 2|E|/(|V|(|V|-1)). Self-loops are not counted, but parallel edges are, so
+// the density of a multigraph may exceed 1. If the graph has fewer than two nodes, 0 is returned.
+func (g *Undirected) Density() float64 {
+	n := float64(g.Order())
+	if n < 2 {
+		return 0
+	}
+	var loops int
+	if st := g.cachedStats(); st != nil {
+		loops = st.loops
+	} else {
+		loops = len(g.SelfLoops())
+	}
+	m := float64(g.Size() - loops)
+	return 2 * m / (n * (n - 1))
+}
+
+// Stats is a summary of the statistics of a graph, as returned by Stats.
+type Stats struct {
+	Order      int     `json:"order"`
+	Size       int     `json:"size"`
+	SelfLoops  int     `json:"selfLoops"`
+	Components int     `json:"components"`
+	MinDegree  int     `json:"minDegree"`
+	MaxDegree  int     `json:"maxDegree"`
+	MeanDegree float64 `json:"meanDegree"`
+	Density    float64 `json:"density"`
+}
+
+// Stats returns a summary of the statistics of the graph. Degrees are as given by Degree and density
+// as given by Density. Components are counted with all edges traversable. The degree statistics of
+// an empty graph are zero.
+func (g *Undirected) Stats() Stats {
+	st := Stats{Order: g.Order(), Size: g.Size()}
+
+	ds := newDisjointSet(len(g.nodes))
+	st.Components = len(g.compNodes)
+	for _, e := range g.compEdges {
+		u, v := e.Nodes()
+		if u == v {
+			st.SelfLoops++
+		} else if ds.union(u.ID(), v.ID()) {
+			st.Components--
+		}
+	}
+
+	for i, n := range g.compNodes {
+		d := n.Degree()
+		if i == 0 || d < st.MinDegree {
+			st.MinDegree = d
+		}
+		if d > st.MaxDegree {
+			st.MaxDegree = d
+		}
+	}
+	if st.Order > 0 {
+		st.MeanDegree = 2 * float64(st.Size) / float64(st.Order)
+	}
+	if st.Order > 1 {
+		st.Density = 2 * float64(st.Size-st.SelfLoops) / float64(st.Order*(st.Order-1))
+	}
+
+	return st
+}
+
+// SelfLoops returns the edges in the graph that join a node to itself.
+func (g *Undirected) SelfLoops() []Edge {
+	var loops []Edge
+	for _, e := range g.compEdges {
+		if e.Head() == e.Tail() {
+			loops = append(loops, e)
+		}
+	}
+	return loops
+}
+
+// DegreeSequence returns the degrees of the nodes of the graph in non-increasing order. Degrees are
+// as given by Degree, so a self-loop contributes two to the degree of its node.
+func (g *Undirected) DegreeSequence() []int {
+	if st := g.cachedStats(); st != nil {
+		return append([]int(nil), st.seq...)
+	}
+	d := make([]int, len(g.compNodes))
+	for i, n := range g.compNodes {
+		d[i] = n.Degree()
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(d)))
+	return d
+}
+
+// DegreeHistogram returns a map from each degree present in the graph to the number of nodes with
+// that degree. Degrees are counted as for DegreeSequence.
+func (g *Undirected) DegreeHistogram() map[int]int {
+	if st := g.cachedStats(); st != nil {
+		h := make(map[int]int, len(st.hist))
+		for d, n := range st.hist {
+			h[d] = n
+		}
+		return h
+	}
+	h := make(map[int]int)
+	for _, n := range g.compNodes {
+		h[n.Degree()]++
+	}
+	return h
+}
+
+// TriangleCount returns the number of triangles in the graph, sets of three nodes that are mutually
+// adjacent. Parallel edges are treated as a single adjacency and self-loops are ignored.
+func (g *Undirected) TriangleCount() int {
+	return len(g.Triangles())
+}
+
+// Triangles returns the triangles in the graph, as for TriangleCount. The nodes of each triangle are
+// given in increasing order of ID.
+func (g *Undirected) Triangles() [][3]Node {
+	all := func(_ Edge) bool { return true }
+	var tri [][3]Node
+	for _, u := range g.nodes {
+		if u == nil {
+			continue
+		}
+		var higher []Node
+		set := make(map[Node]struct{})
+		for _, v := range distinctNeighbors(u, all) {
+			if v.ID() > u.ID() {
+				higher = append(higher, v)
+				set[v] = struct{}{}
+			}
+		}
+		for _, v := range higher {
+			for _, w := range distinctNeighbors(v, all) {
+				if w.ID() <= v.ID() {
+					continue
+				}
+				if _, ok := set[w]; ok {
+					tri = append(tri, [3]Node{u, v, w})
+				}
+			}
+		}
+	}
+	return tri
+}
+
+// KCore returns the nodes of the k-core of the graph, the maximal subgraph in which every node is
+// joined to other nodes of the subgraph by at least k edges satisfying the edge filter ef. Parallel
+// edges are each counted and self-loops are ignored. The k-core is found by repeatedly removing nodes
+// with fewer than k such edges. Nodes are returned in the order given by Nodes.
+func (g *Undirected) KCore(k int, ef EdgeFilter) []Node {
+	deg := make([]int, len(g.nodes))
+	removed := make([]bool, len(g.nodes))
+	var prune []Node
+	for _, n := range g.compNodes {
+		for _, h := range n.Hops(ef) {
+			if h.Node != n {
+				deg[n.ID()]++
+			}
+		}
+		if deg[n.ID()] < k {
+			removed[n.ID()] = true
+			prune = append(prune, n)
+		}
+	}
+	for len(prune) > 0 {
+		n := prune[len(prune)-1]
+		prune = prune[:len(prune)-1]
+		for _, h := range n.Hops(ef) {
+			id := h.Node.ID()
+			if removed[id] {
+				continue
+			}
+			if deg[id]--; deg[id] < k {
+				removed[id] = true
+				prune = append(prune, h.Node)
+			}
+		}
+	}
+
+	var core []Node
+	for _, n := range g.compNodes {
+		if !removed[n.ID()] {
+			core = append(core, n)
+		}
+	}
+	return core
+}
+
+// CoreNumbers returns the core number of each node in the graph keyed by node ID. The core number of
+// a node is the largest k for which the node is in the k-core, as described for KCore with all edges
+// satisfying the edge filter.
+func (g *Undirected) CoreNumbers() map[int]int {
+	all := func(_ Edge) bool { return true }
+	core := make(map[int]int, len(g.compNodes))
+	deg := make([]int, len(g.nodes))
+	removed := make([]bool, len(g.nodes))
+	pq := &PriorityQueue{}
+	for _, n := range g.compNodes {
+		for _, h := range n.Hops(all) {
+			if h.Node != n {
+				deg[n.ID()]++
+			}
+		}
+		pq.Push(n, float64(deg[n.ID()]))
+	}
+	k := 0
+	for pq.Len() > 0 {
+		n, _ := pq.Pop()
+		if d := deg[n.ID()]; d > k {
+			k = d
+		}
+		core[n.ID()] = k
+		removed[n.ID()] = true
+		for _, h := range n.Hops(all) {
+			id := h.Node.ID()
+			if removed[id] {
+				continue
+			}
+			deg[id]--
+			pq.Push(h.Node, float64(deg[id]))
+		}
+	}
+	return core
+}
+
+// statsCache holds graph statistics that are expensive to compute. The statistics are filled lazily by
+// read-only methods, so mu guards them against concurrent readers.
+type statsCache struct {
+	mu    sync.Mutex
+	valid bool
+	loops int
+	seq   []int
+	hist  map[int]int
+}
+
+// EnableStatsCache turns caching of the statistics used by Density, DegreeSequence and
+// DegreeHistogram on or off. When caching is on, the statistics are computed once and reused until the
+// graph is altered by adding or deleting nodes or edges. Order and Size are always constant time.
+// The cache is filled under a lock, so a graph with caching on may be read by concurrent goroutines
+// as long as none of them alters it. The setting is retained by Clone.
+func (g *Undirected) EnableStatsCache(on bool) {
+	switch {
+	case !on:
+		g.stats = nil
+	case g.stats == nil:
+		g.stats = &statsCache{}
+	}
+}
+
+// invalidate marks any cached statistics as stale.
+func (g *Undirected) invalidate() {
+	if g.stats != nil {
+		g.stats.valid = false
+	}
+}
+
+// cachedStats returns the up to date statistics cache of the graph, or nil if caching is off.
+func (g *Undirected) cachedStats() *statsCache {
+	st := g.stats
+	if st == nil {
+		return nil
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.valid {
+		return st
+	}
+	st.loops = 0
+	st.seq = st.seq[:0]
+	st.hist = make(map[int]int)
+	for _, e := range g.compEdges {
+		if e.Head() == e.Tail() {
+			st.loops++
+		}
+	}
+	for _, n := range g.compNodes {
+		d := n.Degree()
+		st.seq = append(st.seq, d)
+		st.hist[d]++
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(st.seq)))
+	st.valid = true
+	return st
+}
+
+// clustering returns the number of adjacent pairs of distinct neighbors of n and the total number of
+// pairs of distinct neighbors.
+func clustering(n Node) (links, pairs float64) {
+	all := func(_ Edge) bool { return true }
+	nbrs := distinctNeighbors(n, all)
+	k := float64(len(nbrs))
+	if k < 2 {
+		return 0, 0
+	}
+	set := make(map[Node]struct{}, len(nbrs))
+	for _, a := range nbrs {
+		set[a] = struct{}{}
+	}
+	for _, a := range nbrs {
+		for _, b := range distinctNeighbors(a, all) {
+			if _, ok := set[b]; ok {
+				links++
+			}
+		}
+	}
+
+	// Each adjacent pair has been seen from both ends.
+	return links / 2, k * (k - 1) / 2
+}
@@ -0,0 +1,288 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"encoding/json"
+	check "launchpad.net/gocheck"
+	"math"
+	"sync"
+)
+
+// Helpers
+func path(c *check.C, n int) *Undirected {
+	var edges []e
+	for i := 0; i < n-1; i++ {
+		edges = append(edges, e{i, i + 1})
+	}
+	return undirected(c, edges)
+}
+
+// Tests
+func (s *S) TestDiameter(c *check.C) {
+	g := path(c, 6)
+	d, ends := g.Diameter(all)
+	c.Check(d, check.Equals, 5.)
+	ids := []int{ends[0].ID(), ends[1].ID()}
+	c.Check(ids[0]+ids[1], check.Equals, 5)
+	c.Check(ids[0]*ids[1], check.Equals, 0)
+	c.Check(g.Eccentricity(g.Node(0), all), check.Equals, 5.)
+	c.Check(g.Eccentricity(g.Node(2), all), check.Equals, 3.)
+
+	g.AddID(6)
+	d, _ = g.Diameter(all)
+	c.Check(math.IsInf(d, 1), check.Equals, true)
+	c.Check(math.IsInf(g.Eccentricity(g.Node(0), all), 1), check.Equals, true)
+
+	d, _ = weightedUndirected(c, wuv).Diameter(all)
+	c.Check(d, check.Equals, 21.)
+}
+
+func (s *S) TestGirth(c *check.C) {
+	c.Check(undirected(c, []e{{0, 1}, {1, 2}, {2, 0}}).Girth(), check.Equals, 3)
+	pentagon := undirected(c, []e{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {4, 0}})
+	c.Check(pentagon.Girth(), check.Equals, 5)
+	for id := 5; id < 8; id++ {
+		pentagon.AddID(id)
+	}
+	pentagon.ConnectByID(0, 5, 1, 0)
+	pentagon.ConnectByID(5, 6, 1, 0)
+	pentagon.ConnectByID(6, 7, 1, 0)
+	pentagon.ConnectByID(7, 5, 1, 0)
+	c.Check(pentagon.Girth(), check.Equals, 3)
+
+	c.Check(path(c, 6).Girth(), check.Equals, -1)
+	c.Check(star(c, 4).Girth(), check.Equals, -1)
+	c.Check(undirected(c, []e{{0, 1}, {1, 2}, {1, 2}}).Girth(), check.Equals, 2)
+	c.Check(undirected(c, []e{{0, 1}, {1, 2}, {2, 0}, {2, 2}}).Girth(), check.Equals, 1)
+	c.Check(NewUndirected().Girth(), check.Equals, -1)
+}
+
+func (s *S) TestClusteringCoefficient(c *check.C) {
+	tri := undirected(c, []e{{0, 1}, {1, 2}, {2, 0}, {0, 1}, {2, 2}})
+	for _, n := range tri.Nodes() {
+		c.Check(tri.ClusteringCoefficient(n), check.Equals, 1.)
+	}
+	c.Check(tri.GlobalClusteringCoefficient(), check.Equals, 1.)
+
+	st := star(c, 4)
+	for _, n := range st.Nodes() {
+		c.Check(st.ClusteringCoefficient(n), check.Equals, 0.)
+	}
+	c.Check(st.GlobalClusteringCoefficient(), check.Equals, 0.)
+
+	st.ConnectByID(1, 2, 1, 0)
+	c.Check(st.ClusteringCoefficient(st.Node(0)), check.Equals, 1./6)
+	c.Check(st.ClusteringCoefficient(st.Node(1)), check.Equals, 1.)
+	c.Check(st.GlobalClusteringCoefficient(), check.Equals, 3./8)
+}
+
+func (s *S) TestLinkPrediction(c *check.C) {
+	// 0 and 1 share the neighbors 2 and 3, and 0 also has the neighbor 4.
+	g := undirected(c, []e{{0, 2}, {0, 3}, {0, 4}, {1, 2}, {1, 3}, {1, 3}, {2, 5}, {0, 0}, {6, 7}})
+	n := g.Node
+	c.Check(g.JaccardSimilarity(n(0), n(1)), check.Equals, 2./3)
+	c.Check(g.JaccardSimilarity(n(1), n(0)), check.Equals, 2./3)
+	c.Check(g.AdamicAdar(n(0), n(1)), check.Equals, 1/math.Log(3)+1/math.Log(2))
+
+	c.Check(g.JaccardSimilarity(n(0), n(6)), check.Equals, 0.)
+	c.Check(g.AdamicAdar(n(0), n(6)), check.Equals, 0.)
+	c.Check(g.JaccardSimilarity(n(6), n(7)), check.Equals, 0.)
+
+	c.Check(g.JaccardSimilarity(n(0), n(0)), check.Equals, 1.)
+	c.Check(g.JaccardSimilarity(n(6), n(6)), check.Equals, 1.)
+	c.Check(g.AdamicAdar(n(6), n(6)), check.Equals, 0.)
+}
+
+func (s *S) TestWeightedNeighborSimilarity(c *check.C) {
+	// 0 and 1 share the neighbors 2 and 3; 0 also has the neighbor 4 and 1 has 5 by a zero-weight edge.
+	g := weightedUndirected(c, []we{{0, 2, 2}, {0, 3, 1}, {0, 3, 1}, {0, 4, 1}, {1, 2, 1}, {1, 3, 3}, {1, 5, 0}, {0, 0, 5}, {6, 7, 1}})
+	n := g.Node
+	// Shared: min(2, 1) + min(2, 3) = 3; total: max(2, 1) + max(2, 3) + 1 = 6.
+	c.Check(g.WeightedNeighborSimilarity(n(0), n(1)), check.Equals, 0.5)
+	c.Check(g.WeightedNeighborSimilarity(n(1), n(0)), check.Equals, 0.5)
+	c.Check(g.WeightedNeighborSimilarity(n(0), n(0)), check.Equals, 1.)
+	c.Check(g.WeightedNeighborSimilarity(n(0), n(6)), check.Equals, 0.)
+	c.Check(g.WeightedNeighborSimilarity(n(5), n(5)), check.Equals, 0.)
+
+	// With unit weights and no parallel edges this is the Jaccard similarity.
+	u := undirected(c, []e{{0, 2}, {0, 3}, {0, 4}, {1, 2}, {1, 3}})
+	c.Check(u.WeightedNeighborSimilarity(u.Node(0), u.Node(1)), check.Equals, u.JaccardSimilarity(u.Node(0), u.Node(1)))
+}
+
+func (s *S) TestDegreeSequence(c *check.C) {
+	st := star(c, 5)
+	c.Check(st.DegreeSequence(), check.DeepEquals, []int{5, 1, 1, 1, 1, 1})
+	c.Check(st.DegreeHistogram(), check.DeepEquals, map[int]int{5: 1, 1: 5})
+
+	st.ConnectByID(1, 1, 1, 0)
+	c.Check(st.DegreeSequence(), check.DeepEquals, []int{5, 3, 1, 1, 1, 1})
+	c.Check(st.DegreeHistogram(), check.DeepEquals, map[int]int{5: 1, 3: 1, 1: 4})
+}
+
+func (s *S) TestDensity(c *check.C) {
+	g := NewUndirected()
+	c.Check(g.Density(), check.Equals, 0.)
+	g.AddID(0)
+	c.Check(g.Density(), check.Equals, 0.)
+	for i := 1; i < 5; i++ {
+		g.AddID(i)
+	}
+	c.Check(g.Density(), check.Equals, 0.)
+	c.Check(g.SelfLoops(), check.HasLen, 0)
+
+	for i := 0; i < 5; i++ {
+		for j := i + 1; j < 5; j++ {
+			g.ConnectByID(i, j, 1, 0)
+		}
+	}
+	c.Check(g.Density(), check.Equals, 1.)
+
+	id, _ := g.ConnectByID(2, 2, 1, 0)
+	loops := g.SelfLoops()
+	c.Assert(loops, check.HasLen, 1)
+	c.Check(loops[0].ID(), check.Equals, id)
+	c.Check(g.Density(), check.Equals, 1.)
+}
+
+func (s *S) TestKCore(c *check.C) {
+	// A 4-clique {0, 1, 2, 3} with a triangle {3, 4, 5} attached at 3 and a tail 5--6--7.
+	g := undirected(c, []e{
+		{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3},
+		{3, 4}, {4, 5}, {5, 3},
+		{5, 6}, {6, 7},
+	})
+	ids := func(nodes []Node) []int {
+		var id []int
+		for _, n := range nodes {
+			id = append(id, n.ID())
+		}
+		return id
+	}
+	c.Check(ids(g.KCore(1, all)), check.DeepEquals, []int{0, 1, 2, 3, 4, 5, 6, 7})
+	c.Check(ids(g.KCore(2, all)), check.DeepEquals, []int{0, 1, 2, 3, 4, 5})
+	c.Check(ids(g.KCore(3, all)), check.DeepEquals, []int{0, 1, 2, 3})
+	c.Check(g.KCore(4, all), check.HasLen, 0)
+	c.Check(ids(g.KCore(2, func(e Edge) bool { return e.Head().ID() != 4 && e.Tail().ID() != 4 })), check.DeepEquals, []int{0, 1, 2, 3})
+
+	c.Check(g.CoreNumbers(), check.DeepEquals, map[int]int{0: 3, 1: 3, 2: 3, 3: 3, 4: 2, 5: 2, 6: 1, 7: 1})
+}
+
+func (s *S) TestTriangles(c *check.C) {
+	tri := undirected(c, []e{{0, 1}, {1, 2}, {2, 0}, {0, 1}, {2, 2}})
+	c.Check(tri.TriangleCount(), check.Equals, 1)
+	ts := tri.Triangles()
+	c.Assert(ts, check.HasLen, 1)
+	c.Check([]int{ts[0][0].ID(), ts[0][1].ID(), ts[0][2].ID()}, check.DeepEquals, []int{0, 1, 2})
+
+	k4 := undirected(c, []e{{0, 1}, {0, 2}, {0, 3}, {1, 2}, {1, 3}, {2, 3}})
+	c.Check(k4.TriangleCount(), check.Equals, 4)
+	for _, t := range k4.Triangles() {
+		c.Check(t[0].ID() < t[1].ID() && t[1].ID() < t[2].ID(), check.Equals, true)
+	}
+
+	c.Check(star(c, 4).TriangleCount(), check.Equals, 0)
+}
+
+func (s *S) TestStats(c *check.C) {
+	// A triangle with a self-loop on 0 and a pendant 3, a separate edge 4-5 and an isolated node 6.
+	g := undirected(c, []e{{0, 1}, {1, 2}, {2, 0}, {0, 0}, {2, 3}, {4, 5}})
+	g.AddID(6)
+	st := g.Stats()
+	c.Check(st, check.DeepEquals, Stats{
+		Order:      7,
+		Size:       6,
+		SelfLoops:  1,
+		Components: 3,
+		MinDegree:  0,
+		MaxDegree:  4,
+		MeanDegree: 12. / 7,
+		Density:    10. / 42,
+	})
+	c.Check(st.Density, check.Equals, g.Density())
+	c.Check(st.Components, check.Equals, len(g.ConnectedComponents(all)))
+	seq := g.DegreeSequence()
+	c.Check(st.MaxDegree, check.Equals, seq[0])
+	c.Check(st.MinDegree, check.Equals, seq[len(seq)-1])
+
+	b, err := json.Marshal(st)
+	c.Assert(err, check.IsNil)
+	var rst Stats
+	c.Assert(json.Unmarshal(b, &rst), check.IsNil)
+	c.Check(rst, check.DeepEquals, st)
+
+	c.Check(NewUndirected().Stats(), check.DeepEquals, Stats{})
+}
+
+func (s *S) TestStatsCache(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	g.EnableStatsCache(true)
+	checkStats := func() {
+		fresh := g.Clone()
+		c.Check(fresh.stats != nil, check.Equals, g.stats != nil)
+		fresh.EnableStatsCache(false)
+		c.Check(g.Density(), check.Equals, fresh.Density())
+		c.Check(g.DegreeSequence(), check.DeepEquals, fresh.DegreeSequence())
+		c.Check(g.DegreeHistogram(), check.DeepEquals, fresh.DegreeHistogram())
+	}
+	checkStats()
+
+	g.AddID(6)
+	checkStats()
+	g.ConnectByID(6, 0, 1, 0)
+	g.ConnectByID(6, 6, 1, 0)
+	checkStats()
+	g.DegreeSequence()[0] = -1
+	g.DegreeHistogram()[1] = -1
+	checkStats()
+	g.RemoveEdgesFunc(func(e Edge) bool { return e.Weight() > 10 })
+	checkStats()
+	g.RemoveNodesFunc(func(n Node) bool { return n.ID() == 2 })
+	checkStats()
+	g.Merge(g.Node(0), g.Node(1))
+	checkStats()
+	for _, e := range g.Edges() {
+		if e.Head() != e.Tail() {
+			g.DeleteEdge(e)
+			break
+		}
+	}
+	checkStats()
+
+	g.EnableStatsCache(false)
+	g.AddID(7)
+	checkStats()
+}
+
+func (s *S) TestStatsCacheConcurrentReads(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	want := g.DegreeSequence()
+	g.EnableStatsCache(true)
+	var wg sync.WaitGroup
+	seqs := make([][]int, 8)
+	for i := range seqs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			g.Density()
+			seqs[i] = g.DegreeSequence()
+		}(i)
+	}
+	wg.Wait()
+	for _, seq := range seqs {
+		c.Check(seq, check.DeepEquals, want)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// WriteMatrixMarket writes the graph to w as a symmetric real Matrix Market coordinate matrix with
+// NextNodeID rows and columns. Each edge is written, in order of edge ID, as an entry in the lower
+// triangle at the row and column given by the IDs of its nodes plus one, with the edge weight as its
+// value. Parallel edges give rise to repeated entries.
+func (g *Undirected) WriteMatrixMarket(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "%%MatrixMarket matrix coordinate real symmetric")
+	fmt.Fprintf(bw, "%d %d %d\n", len(g.nodes), len(g.nodes), len(g.compEdges))
+	for _, e := range g.edges {
+		if e == nil {
+			continue
+		}
+		i, j := e.Tail().ID(), e.Head().ID()
+		if i < j {
+			i, j = j, i
+		}
+		fmt.Fprintf(bw, "%d %d %s\n", i+1, j+1, strconv.FormatFloat(e.Weight(), 'g', -1, 64))
+	}
+
+	return bw.Flush()
+}
+
+// ReadMatrixMarket reads a symmetric Matrix Market coordinate matrix from r and returns the graph it
+// describes. The graph has a node for each row of the matrix, with IDs from zero, and an edge for each
+// entry joining the nodes of its row and column, with the entry's value as its weight. Entries of
+// pattern matrices are given a weight of 1. Real and integer matrices are supported.
+func ReadMatrixMarket(r io.Reader) (*Undirected, error) {
+	sc := bufio.NewScanner(r)
+	line := 0
+	errorf := func(format string, args ...interface{}) error {
+		return fmt.Errorf("graph: mtx: line %d: %s", line, fmt.Sprintf(format, args...))
+	}
+
+	if !sc.Scan() {
+		if err := sc.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("graph: mtx: empty input")
+	}
+	line++
+	header := strings.Fields(strings.ToLower(sc.Text()))
+	if len(header) != 5 || header[0] != "%%matrixmarket" || header[1] != "matrix" {
+		return nil, errorf("invalid header")
+	}
+	if header[2] != "coordinate" {
+		return nil, errorf("unsupported format %q", header[2])
+	}
+	var pattern bool
+	switch header[3] {
+	case "real", "integer":
+	case "pattern":
+		pattern = true
+	default:
+		return nil, errorf("unsupported field %q", header[3])
+	}
+	if header[4] != "symmetric" {
+		return nil, errorf("unsupported symmetry %q", header[4])
+	}
+
+	var (
+		g       *Undirected
+		n, nnz  int
+		entries int
+	)
+	for sc.Scan() {
+		line++
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || text[0] == '%' {
+			continue
+		}
+		f := strings.Fields(text)
+		if g == nil {
+			var m int
+			if len(f) != 3 {
+				return nil, errorf("invalid size line")
+			}
+			var err error
+			if m, err = strconv.Atoi(f[0]); err != nil || m < 0 || m > MaxReadID+1 {
+				return nil, errorf("invalid row count %q", f[0])
+			}
+			if n, err = strconv.Atoi(f[1]); err != nil || n != m {
+				return nil, errorf("matrix is not square")
+			}
+			if nnz, err = strconv.Atoi(f[2]); err != nil || nnz < 0 {
+				return nil, errorf("invalid entry count %q", f[2])
+			}
+			g = NewUndirected()
+			for id := 0; id < n; id++ {
+				if _, err := g.AddID(id); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+
+		if pattern && len(f) != 2 || !pattern && len(f) != 3 {
+			return nil, errorf("invalid entry")
+		}
+		var ids [2]int
+		for k := range ids {
+			i, err := strconv.Atoi(f[k])
+			if err != nil || i < 1 || i > n {
+				return nil, errorf("invalid index %q", f[k])
+			}
+			ids[k] = i - 1
+		}
+		w := 1.
+		if !pattern {
+			var err error
+			if w, err = strconv.ParseFloat(f[2], 64); err != nil {
+				return nil, errorf("invalid value %q", f[2])
+			}
+		}
+		if entries++; entries > nnz {
+			return nil, errorf("too many entries")
+		}
+		if _, err := g.ConnectByID(ids[0], ids[1], w, 0); err != nil {
+			return nil, err
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	if g == nil {
+		return nil, fmt.Errorf("graph: mtx: missing size line")
+	}
+	if entries != nnz {
+		return nil, fmt.Errorf("graph: mtx: expected %d entries, found %d", nnz, entries)
+	}
+
+	return g, nil
+}
@@ -0,0 +1,80 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"bytes"
+	check "launchpad.net/gocheck"
+	"strings"
+)
+
+// Tests
+var mtxSymmetric = `%%MatrixMarket matrix coordinate real symmetric
+5 5 4
+2 1 1
+3 2 2.5
+3 1 -3
+5 5 1
+`
+
+func (s *S) TestWriteMatrixMarket(c *check.C) {
+	g := weightedUndirected(c, []we{{0, 1, 1}, {1, 2, 2.5}, {2, 0, -3}, {4, 4, 1}})
+	var buf bytes.Buffer
+	c.Assert(g.WriteMatrixMarket(&buf), check.IsNil)
+	c.Check(buf.String(), check.Equals, mtxSymmetric)
+}
+
+func (s *S) TestReadMatrixMarket(c *check.C) {
+	g, err := ReadMatrixMarket(strings.NewReader(mtxSymmetric))
+	c.Assert(err, check.IsNil)
+	c.Check(g.Order(), check.Equals, 5)
+	c.Check(g.Size(), check.Equals, 4)
+	c.Check(g.Edge(1).Weight(), check.Equals, 2.5)
+	var buf bytes.Buffer
+	c.Assert(g.WriteMatrixMarket(&buf), check.IsNil)
+	c.Check(buf.String(), check.Equals, mtxSymmetric)
+
+	g, err = ReadMatrixMarket(strings.NewReader(`%%MatrixMarket matrix coordinate pattern symmetric
+% a triangle
+%
+3 3 3
+
+2 1
+3 1
+3 2
+`))
+	c.Assert(err, check.IsNil)
+	c.Check(g.Order(), check.Equals, 3)
+	c.Check(g.Size(), check.Equals, 3)
+	c.Check(g.Edge(2).Weight(), check.Equals, 1.)
+	c.Check(g.TriangleCount(), check.Equals, 1)
+
+	for _, t := range []struct {
+		mtx string
+		err string
+	}{
+		{"%%MatrixMarket matrix coordinate real general\n2 2 1\n1 2 1\n", `graph: mtx: line 1: unsupported symmetry "general"`},
+		{"%%MatrixMarket matrix array real symmetric\n2 2\n", `graph: mtx: line 1: unsupported format "array"`},
+		{"%%MatrixMarket matrix coordinate real symmetric\n2 3 1\n", "graph: mtx: line 2: matrix is not square"},
+		{"%%MatrixMarket matrix coordinate real symmetric\n400000000 400000000 0\n", `graph: mtx: line 2: invalid row count "400000000"`},
+		{"%%MatrixMarket matrix coordinate real symmetric\n2 2 1\n3 1 1\n", `graph: mtx: line 3: invalid index "3"`},
+		{"%%MatrixMarket matrix coordinate real symmetric\n2 2 2\n2 1 1\n", "graph: mtx: expected 2 entries, found 1"},
+		{"", "graph: mtx: empty input"},
+	} {
+		_, err = ReadMatrixMarket(strings.NewReader(t.mtx))
+		c.Check(err, check.ErrorMatches, t.err)
+	}
+}
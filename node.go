@@ -23,7 +23,15 @@ type Node interface {
 	ID() int
 	Edges() []Edge
 	Degree() int
+	SimpleDegree(EdgeFilter) int
+	Strength(EdgeFilter) float64
+	OutDegree(EdgeFilter) int
+	InDegree(EdgeFilter) int
 	Neighbors(EdgeFilter) []Node
+	EachNeighbor(EdgeFilter, func(Node) bool)
+	UniqueNeighbors(EdgeFilter) []Node
+	OutNeighbors(EdgeFilter) []Node
+	InNeighbors(EdgeFilter) []Node
 	Hops(EdgeFilter) []*Hop
 	String() string
 
@@ -42,9 +50,10 @@ type NodeFilter func(Node) bool
 
 // A Node is a node in a graph.
 type node struct {
-	id    int
-	i     int
-	edges Edges
+	id       int
+	i        int
+	edges    Edges
+	directed bool
 }
 
 // newNode creates a new *Nodes with ID id. Nodes should only ever exist in the context of a
@@ -71,7 +80,8 @@ func (n *node) Edges() []Edge {
 	return n.edges
 }
 
-// Degree returns the number of incident edges on a node. Looped edges are counted at both ends.
+// Degree returns the number of incident edges on a node. Looped edges are counted at both ends, so
+// each self-loop adds two to the degree.
 func (n *node) Degree() int {
 	l := 0
 	for _, e := range n.edges {
@@ -82,13 +92,77 @@ func (n *node) Degree() int {
 	return l + len(n.edges)
 }
 
+// SimpleDegree returns the number of edges satisfying the edge filter ef that are incident on the
+// node, counting each edge once. Unlike Degree, a self-loop adds one to the degree.
+func (n *node) SimpleDegree(ef EdgeFilter) int {
+	d := 0
+	for _, e := range n.edges {
+		if ef(e) {
+			d++
+		}
+	}
+	return d
+}
+
+// Strength returns the sum of the weights of the edges satisfying the edge filter ef that are
+// incident on the node. Looped edges are counted at both ends as for Degree.
+func (n *node) Strength(ef EdgeFilter) float64 {
+	var s float64
+	for _, e := range n.edges {
+		if !ef(e) {
+			continue
+		}
+		if e.Head() == e.Tail() {
+			s += 2 * e.Weight()
+		} else {
+			s += e.Weight()
+		}
+	}
+	return s
+}
+
+// OutDegree returns the number of edges satisfying the edge filter ef that leave the node. For a node
+// in a directed graph these are the edges with the node as their tail. For a node in an undirected
+// graph every incident edge is counted, with looped edges counted at both ends as for Degree.
+func (n *node) OutDegree(ef EdgeFilter) int {
+	return n.degree(ef, func(e Edge) Node { return e.Tail() })
+}
+
+// InDegree returns the number of edges satisfying the edge filter ef that enter the node. For a node
+// in a directed graph these are the edges with the node as their head. For a node in an undirected
+// graph every incident edge is counted, with looped edges counted at both ends as for Degree.
+func (n *node) InDegree(ef EdgeFilter) int {
+	return n.degree(ef, func(e Edge) Node { return e.Head() })
+}
+
+func (n *node) degree(ef EdgeFilter, end func(Edge) Node) int {
+	d := 0
+	for _, e := range n.edges {
+		if !ef(e) {
+			continue
+		}
+		switch {
+		case n.directed:
+			if end(e) == n {
+				d++
+			}
+		case e.Head() == e.Tail():
+			d += 2
+		default:
+			d++
+		}
+	}
+	return d
+}
+
 // Neighbors returns a slice of nodes that share an edge with the node. Multiply connected nodes are
 // repeated in the slice. If the node is n-connected it will be included in the slice, potentially
-// repeatedly if there are multiple n-connecting edges.
+// repeatedly if there are multiple n-connecting edges. For a node in a directed graph only edges
+// leaving the node are followed, so Neighbors is the same as OutNeighbors.
 func (n *node) Neighbors(ef EdgeFilter) []Node {
 	var nodes []Node
 	for _, e := range n.edges {
-		if ef(e) {
+		if n.follows(e) && ef(e) {
 			if a := e.Tail(); a == n {
 				nodes = append(nodes, e.Head())
 			} else {
@@ -99,12 +173,80 @@ func (n *node) Neighbors(ef EdgeFilter) []Node {
 	return nodes
 }
 
+// UniqueNeighbors returns a slice of the nodes joined to the node by edges satisfying the edge
+// filter ef, with each node included only once, in the order first seen in Neighbors. A node with a
+// self-loop satisfying ef is included in its own neighbors once.
+func (n *node) UniqueNeighbors(ef EdgeFilter) []Node {
+	var nodes []Node
+	seen := make(map[Node]struct{})
+	n.EachNeighbor(ef, func(a Node) bool {
+		if _, ok := seen[a]; !ok {
+			seen[a] = struct{}{}
+			nodes = append(nodes, a)
+		}
+		return true
+	})
+	return nodes
+}
+
+// EachNeighbor calls fn for each node joined to the node by an edge satisfying the edge filter ef,
+// in the order given by Neighbors, stopping if fn returns false. Unlike Neighbors, no slice is
+// allocated. As for Neighbors, only edges leaving a node in a directed graph are followed.
+func (n *node) EachNeighbor(ef EdgeFilter, fn func(Node) bool) {
+	for _, e := range n.edges {
+		if !n.follows(e) || !ef(e) {
+			continue
+		}
+		a := e.Tail()
+		if a == n {
+			a = e.Head()
+		}
+		if !fn(a) {
+			return
+		}
+	}
+}
+
+// OutNeighbors returns a slice of nodes at the far end of edges satisfying the edge filter ef that
+// leave the node, as counted by OutDegree. For a node in an undirected graph this is the same as
+// Neighbors except that a self-loop gives the node twice, once for each end.
+func (n *node) OutNeighbors(ef EdgeFilter) []Node {
+	return n.neighbors(ef, func(e Edge) Node { return e.Tail() })
+}
+
+// InNeighbors returns a slice of nodes at the far end of edges satisfying the edge filter ef that
+// enter the node, as counted by InDegree. For a node in an undirected graph this is the same as
+// Neighbors except that a self-loop gives the node twice, once for each end.
+func (n *node) InNeighbors(ef EdgeFilter) []Node {
+	return n.neighbors(ef, func(e Edge) Node { return e.Head() })
+}
+
+func (n *node) neighbors(ef EdgeFilter, end func(Edge) Node) []Node {
+	var nodes []Node
+	for _, e := range n.edges {
+		if !ef(e) {
+			continue
+		}
+		switch {
+		case n.directed:
+			if end(e) == n {
+				nodes = append(nodes, adjacent(e, n))
+			}
+		case e.Head() == e.Tail():
+			nodes = append(nodes, n, n)
+		default:
+			nodes = append(nodes, adjacent(e, n))
+		}
+	}
+	return nodes
+}
+
 // Hops has essentially the same functionality as Neighbors with the exception that the connecting
 // edge is also returned.
 func (n *node) Hops(ef EdgeFilter) []*Hop {
 	var h []*Hop
 	for _, e := range n.edges {
-		if ef(e) {
+		if n.follows(e) && ef(e) {
 			if a := e.Tail(); a == n {
 				h = append(h, &Hop{e, e.Head()})
 			} else {
@@ -115,6 +257,24 @@ func (n *node) Hops(ef EdgeFilter) []*Hop {
 	return h
 }
 
+// follows returns whether the edge e may be traversed away from the node: any incident edge of an
+// undirected node, or an edge with the node as its tail for a directed node.
+func (n *node) follows(e Edge) bool {
+	return !n.directed || e.Tail() == n
+}
+
+// distinctNeighbors returns the nodes other than n that share an edge satisfying ef with n, each
+// included only once.
+func distinctNeighbors(n Node, ef EdgeFilter) []Node {
+	nodes := n.UniqueNeighbors(ef)
+	for i, a := range nodes {
+		if a == n {
+			return append(nodes[:i], nodes[i+1:]...)
+		}
+	}
+	return nodes
+}
+
 func (n *node) add(e Edge) { n.edges = append(n.edges, e) }
 
 func (n *node) dropAll() {
@@ -169,9 +329,9 @@ func (ns Nodes) BuildUndirected(compact bool) (*Undirected, error) {
 			g.AddID(vid)
 			var ne Edge
 			if compact {
-				ne = g.newEdge(g.nodes[uid], g.nodes[vid], e.Weight(), e.Flags())
+				ne = g.newEdge(g.nodes[uid], g.nodes[vid], e.Weight(), e.Flags(), e.Label())
 			} else {
-				ne = g.newEdgeKeepID(e.ID(), g.nodes[uid], g.nodes[vid], e.Weight(), e.Flags())
+				ne = g.newEdgeKeepID(e.ID(), g.nodes[uid], g.nodes[vid], e.Weight(), e.Flags(), e.Label())
 			}
 			g.nodes[uid].add(ne)
 			if vid != uid {
@@ -0,0 +1,76 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+var DegenerateWeights = errors.New("graph: weights cannot be normalized")
+
+// NormMode specifies how edge weights are rescaled by NormalizeWeights.
+type NormMode int
+
+const (
+	NormSum    NormMode = iota // Divide weights by their sum so that they sum to 1.
+	NormMax                    // Divide weights by the largest absolute weight.
+	NormMinMax                 // Rescale weights linearly so that they span [0, 1].
+)
+
+// NormalizeWeights rescales the weights of all the edges in the graph according to mode. For
+// NormSum, if any weight is negative the error NegativeWeight is returned. If the weights sum to
+// zero for NormSum, are all zero for NormMax or are all equal for NormMinMax, the error
+// DegenerateWeights is returned. The graph is not altered if an error is returned. A graph with no
+// edges is left unaltered.
+func (g *Undirected) NormalizeWeights(mode NormMode) error {
+	if len(g.compEdges) == 0 {
+		return nil
+	}
+
+	var scale, offset float64
+	switch mode {
+	case NormSum:
+		for _, e := range g.compEdges {
+			w := e.Weight()
+			if w < 0 {
+				return NegativeWeight
+			}
+			scale += w
+		}
+	case NormMax:
+		for _, e := range g.compEdges {
+			scale = math.Max(scale, math.Abs(e.Weight()))
+		}
+	case NormMinMax:
+		min, max := math.Inf(1), math.Inf(-1)
+		for _, e := range g.compEdges {
+			min, max = math.Min(min, e.Weight()), math.Max(max, e.Weight())
+		}
+		scale, offset = max-min, min
+	default:
+		panic("graph: invalid normalization mode")
+	}
+	if scale == 0 {
+		return DegenerateWeights
+	}
+
+	for _, e := range g.compEdges {
+		e.SetWeight((e.Weight() - offset) / scale)
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+)
+
+// Tests
+func (s *S) TestNormalizeWeights(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	c.Assert(g.NormalizeWeights(NormSum), check.IsNil)
+	var sum float64
+	for _, e := range g.Edges() {
+		sum += e.Weight()
+	}
+	c.Check(math.Abs(sum-1) < 1e-12, check.Equals, true, check.Commentf("sum=%v", sum))
+	c.Check(g.Edge(0).Weight(), check.Equals, 7./83)
+
+	g = weightedUndirected(c, []we{{0, 1, -2}, {1, 2, 1}, {2, 0, 4}})
+	c.Check(g.NormalizeWeights(NormSum), check.Equals, NegativeWeight)
+	c.Check(g.Edge(0).Weight(), check.Equals, -2.)
+
+	c.Assert(g.NormalizeWeights(NormMax), check.IsNil)
+	c.Check([]float64{g.Edge(0).Weight(), g.Edge(1).Weight(), g.Edge(2).Weight()}, check.DeepEquals, []float64{-0.5, 0.25, 1})
+
+	c.Assert(g.NormalizeWeights(NormMinMax), check.IsNil)
+	c.Check([]float64{g.Edge(0).Weight(), g.Edge(1).Weight(), g.Edge(2).Weight()}, check.DeepEquals, []float64{0, 0.5, 1})
+
+	g = weightedUndirected(c, []we{{0, 1, 0}, {1, 2, 0}})
+	c.Check(g.NormalizeWeights(NormSum), check.Equals, DegenerateWeights)
+	c.Check(g.NormalizeWeights(NormMax), check.Equals, DegenerateWeights)
+	c.Check(g.NormalizeWeights(NormMinMax), check.Equals, DegenerateWeights)
+	c.Check(NewUndirected().NormalizeWeights(NormSum), check.IsNil)
+	c.Check(func() { g.NormalizeWeights(NormMode(-1)) }, check.Panics, "graph: invalid normalization mode")
+}
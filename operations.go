@@ -0,0 +1,108 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+// pairKey is an unordered pair of node IDs.
+type pairKey struct{ u, v int }
+
+func pairOf(e Edge) pairKey {
+	u, v := e.Head().ID(), e.Tail().ID()
+	if u > v {
+		u, v = v, u
+	}
+	return pairKey{u, v}
+}
+
+// Union returns a new graph holding every node of a and b, matched by node ID. Edges are matched by
+// the IDs of the nodes they join, so that if a pair of nodes is joined by k edges in a and by l edges
+// in b, the union joins them by max(k, l) edges. Edges are taken from a in ID order and then from b
+// for any pairs where b has more edges than a, with weights and flags copied from the source edge.
+// Edges in the new graph are given new IDs in the order they are added.
+func Union(a, b *Undirected) (*Undirected, error) {
+	g := NewUndirected()
+	for _, s := range []*Undirected{a, b} {
+		for _, n := range s.nodes {
+			if n != nil {
+				g.AddID(n.ID())
+			}
+		}
+	}
+
+	count := make(map[pairKey]int)
+	for _, e := range a.edges {
+		if e == nil {
+			continue
+		}
+		count[pairOf(e)]++
+		if _, err := g.ConnectByID(e.Tail().ID(), e.Head().ID(), e.Weight(), e.Flags()); err != nil {
+			return nil, err
+		}
+	}
+	for _, e := range b.edges {
+		if e == nil {
+			continue
+		}
+		k := pairOf(e)
+		if count[k] > 0 {
+			count[k]--
+			continue
+		}
+		if _, err := g.ConnectByID(e.Tail().ID(), e.Head().ID(), e.Weight(), e.Flags()); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+// Intersection returns a new graph holding the nodes present in both a and b, matched by node ID.
+// Edges are matched by the IDs of the nodes they join, so that if a pair of nodes is joined by k edges
+// in a and by l edges in b, the intersection joins them by min(k, l) edges. The edges retained are the
+// first of a's edges in ID order joining the pair, with weights and flags copied from a. Edges in the
+// new graph are given new IDs in the order they are added.
+func Intersection(a, b *Undirected) (*Undirected, error) {
+	g := NewUndirected()
+	for _, n := range a.nodes {
+		if n == nil {
+			continue
+		}
+		if ok, _ := b.HasNodeID(n.ID()); ok {
+			g.AddID(n.ID())
+		}
+	}
+
+	count := make(map[pairKey]int)
+	for _, e := range b.edges {
+		if e != nil {
+			count[pairOf(e)]++
+		}
+	}
+	for _, e := range a.edges {
+		if e == nil {
+			continue
+		}
+		k := pairOf(e)
+		if count[k] == 0 {
+			continue
+		}
+		count[k]--
+		if _, err := g.ConnectByID(e.Tail().ID(), e.Head().ID(), e.Weight(), e.Flags()); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
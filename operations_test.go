@@ -0,0 +1,55 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+)
+
+// Tests
+func (s *S) TestUnionIntersection(c *check.C) {
+	a := weightedUndirected(c, []we{{0, 1, 1}, {1, 2, 2}, {1, 2, 3}, {2, 3, 4}})
+	b := weightedUndirected(c, []we{{2, 1, 5}, {3, 4, 6}, {0, 1, 7}, {0, 1, 8}})
+
+	u, err := Union(a, b)
+	c.Assert(err, check.IsNil)
+	c.Check(u.Order(), check.Equals, 5)
+	c.Check(u.Size(), check.Equals, 6)
+	ce, _ := u.ConnectingEdges(u.Node(0), u.Node(1))
+	c.Check(len(ce), check.Equals, 2)
+	ce, _ = u.ConnectingEdges(u.Node(1), u.Node(2))
+	c.Check(len(ce), check.Equals, 2)
+	c.Check(u.Edge(0).Weight(), check.Equals, 1.)
+	c.Check(u.Edge(4).Weight(), check.Equals, 6.)
+	c.Check(u.Edge(5).Weight(), check.Equals, 8.)
+
+	i, err := Intersection(a, b)
+	c.Assert(err, check.IsNil)
+	c.Check(i.Order(), check.Equals, 4)
+	c.Check(i.Size(), check.Equals, 2)
+	c.Check(i.Edge(0).Weight(), check.Equals, 1.)
+	c.Check(i.Edge(1).Weight(), check.Equals, 2.)
+
+	d := weightedUndirected(c, []we{{5, 6, 1}})
+	u, err = Union(a, d)
+	c.Assert(err, check.IsNil)
+	c.Check(u.Order(), check.Equals, 6)
+	c.Check(u.Size(), check.Equals, 5)
+	i, err = Intersection(a, d)
+	c.Assert(err, check.IsNil)
+	c.Check(i.Order(), check.Equals, 0)
+	c.Check(i.Size(), check.Equals, 0)
+}
@@ -0,0 +1,64 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+// AllSimplePaths returns every path from the node s to the node t that visits no node more than once,
+// traversing edges that satisfy the edge filter ef. Paths are returned as ordered slices of edges and
+// are found by depth first search. Parallel edges give rise to distinct paths. If maxLen is greater
+// than zero, only paths of at most maxLen edges are returned; otherwise path length is unbounded. Note
+// that the number of simple paths may grow exponentially with the size of the graph, so an unbounded
+// search should only be used on small or sparse graphs. If s and t are the same node, the only path
+// is the empty path. If either node does not exist in the graph, nil is returned.
+func (g *Undirected) AllSimplePaths(s, t Node, maxLen int, ef EdgeFilter) [][]Edge {
+	if ok, _ := g.Has(s); !ok {
+		return nil
+	}
+	if ok, _ := g.Has(t); !ok {
+		return nil
+	}
+	if s == t {
+		return [][]Edge{{}}
+	}
+
+	var (
+		paths  [][]Edge
+		path   []Edge
+		onPath = make([]bool, g.NextNodeID())
+		walk   func(n Node)
+	)
+	walk = func(n Node) {
+		if n == t {
+			paths = append(paths, append([]Edge(nil), path...))
+			return
+		}
+		if maxLen > 0 && len(path) == maxLen {
+			return
+		}
+		onPath[n.ID()] = true
+		for _, h := range n.Hops(ef) {
+			if onPath[h.Node.ID()] {
+				continue
+			}
+			path = append(path, h.Edge)
+			walk(h.Node)
+			path = path[:len(path)-1]
+		}
+		onPath[n.ID()] = false
+	}
+	walk(s)
+
+	return paths
+}
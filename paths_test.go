@@ -0,0 +1,44 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+)
+
+// Tests
+func (s *S) TestAllSimplePaths(c *check.C) {
+	// A diamond with an extra chord that is excluded by the edge filter.
+	g := undirected(c, []e{{0, 1}, {0, 2}, {1, 3}, {2, 3}, {1, 2}})
+	noChord := func(e Edge) bool { return e.ID() != 4 }
+	paths := g.AllSimplePaths(g.Node(0), g.Node(3), 0, noChord)
+	c.Assert(len(paths), check.Equals, 2)
+	var ids [][]int
+	for _, p := range paths {
+		c.Check(pathNodes(g.Node(0), p), check.HasLen, 3)
+		var id []int
+		for _, e := range p {
+			id = append(id, e.ID())
+		}
+		ids = append(ids, id)
+	}
+	c.Check(ids, check.DeepEquals, [][]int{{0, 2}, {1, 3}})
+
+	c.Check(g.AllSimplePaths(g.Node(0), g.Node(3), 0, all), check.HasLen, 4)
+	c.Check(g.AllSimplePaths(g.Node(0), g.Node(3), 2, all), check.HasLen, 2)
+	c.Check(g.AllSimplePaths(g.Node(0), g.Node(3), 1, all), check.HasLen, 0)
+	c.Check(g.AllSimplePaths(g.Node(0), g.Node(0), 0, all), check.DeepEquals, [][]Edge{{}})
+}
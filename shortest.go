@@ -0,0 +1,603 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"sync"
+)
+
+var NegativeWeight = errors.New("graph: negative edge weight")
+
+// CostFunc is a function type used to determine the cost of traversing an edge.
+type CostFunc func(Edge) float64
+
+// ShortestPath returns the lowest cost path from the node from to the node to, traversing edges that
+// satisfy the edge filter ef and using edge weights as distances. If either node does not exist in the
+// graph an appropriate error is returned. If an edge with a negative weight is encountered, the error
+// NegativeWeight is returned. If to cannot be reached from from, cost is +Inf and a not found error
+// is returned.
+func (g *Undirected) ShortestPath(from, to Node, ef EdgeFilter) (path []Edge, cost float64, err error) {
+	return g.ShortestPathCost(from, to, ef, nil)
+}
+
+// ShortestPathCost returns the lowest cost path from the node from to the node to as ShortestPath
+// does, but with the cost of each edge given by the cost function cf, allowing edges to be penalized,
+// for example according to their flags, without altering their weights. cf is called once each time
+// an edge is relaxed. If cf is nil, edge weights are used. If cf returns a negative cost, the error
+// NegativeWeight is returned.
+func (g *Undirected) ShortestPathCost(from, to Node, ef EdgeFilter, cf CostFunc) (path []Edge, cost float64, err error) {
+	var ok bool
+	ok, err = g.Has(from)
+	if !ok {
+		if err == nil {
+			err = NodeDoesNotExist
+		}
+		return nil, math.Inf(1), err
+	}
+	ok, err = g.Has(to)
+	if !ok {
+		if err == nil {
+			err = NodeDoesNotExist
+		}
+		return nil, math.Inf(1), err
+	}
+
+	dist, pred, err := g.dijkstra(from, to, ef, cf)
+	if err != nil {
+		return nil, math.Inf(1), err
+	}
+	cost, ok = dist[to.ID()]
+	if !ok {
+		return nil, math.Inf(1), notFound
+	}
+
+	return pathTo(from, to, pred), cost, nil
+}
+
+// ShortestPaths returns the shortest path distances from the node from to each node reachable from it
+// via edges that satisfy the edge filter ef, and the edge leading into each node on its shortest path.
+// Both maps are keyed by node ID; nodes that cannot be reached do not appear. Paths to any reachable
+// node can be reconstructed by following pred back to from. If an edge with a negative weight is
+// encountered, the error NegativeWeight is returned.
+func (g *Undirected) ShortestPaths(from Node, ef EdgeFilter) (dist map[int]float64, pred map[int]Edge, err error) {
+	ok, err := g.Has(from)
+	if !ok {
+		if err == nil {
+			err = NodeDoesNotExist
+		}
+		return nil, nil, err
+	}
+	return g.dijkstra(from, nil, ef, nil)
+}
+
+// BFSLevels returns the number of edges on a shortest path from the node s to each node reachable
+// from it via edges that satisfy the edge filter ef, keyed by node ID. This is the unweighted analog of
+// ShortestPaths, found by a breadth-first search. Nodes that cannot be reached do not appear. If s
+// does not exist in the graph, nil is returned.
+func (g *Undirected) BFSLevels(s Node, ef EdgeFilter) map[int]int {
+	if ok, _ := g.Has(s); !ok {
+		return nil
+	}
+
+	levels := map[int]int{s.ID(): 0}
+	q := &queue{}
+	q.Enqueue(s)
+	for q.Len() > 0 {
+		u, _ := q.Dequeue()
+		d := levels[u.ID()] + 1
+		u.EachNeighbor(ef, func(v Node) bool {
+			if _, ok := levels[v.ID()]; !ok {
+				levels[v.ID()] = d
+				q.Enqueue(v)
+			}
+			return true
+		})
+	}
+
+	return levels
+}
+
+// MultiSourceShortestPaths returns the shortest path distances from each node in sources to each node
+// reachable from it, keyed by source ID and then by target ID. An independent Dijkstra search over
+// all edges is made from each source, with the searches shared between up to threads goroutines,
+// bounded by MaxProcs. Sources that do not exist in the graph, and sources whose search encounters an
+// edge with a negative weight, are not included in the result.
+func (g *Undirected) MultiSourceShortestPaths(sources []Node, threads int) map[int]map[int]float64 {
+	if threads > MaxProcs {
+		threads = MaxProcs
+	}
+	if threads > len(sources) {
+		threads = len(sources)
+	}
+	if threads < 1 {
+		threads = 1
+	}
+
+	all := func(_ Edge) bool { return true }
+	dists := make([]map[int]float64, len(sources))
+	work := make(chan int)
+	wg := &sync.WaitGroup{}
+	for j := 0; j < threads; j++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				dist, _, err := g.ShortestPaths(sources[i], all)
+				if err == nil {
+					dists[i] = dist
+				}
+			}
+		}()
+	}
+	for i := range sources {
+		work <- i
+	}
+	close(work)
+	wg.Wait()
+
+	res := make(map[int]map[int]float64, len(sources))
+	for i, d := range dists {
+		if d != nil {
+			res[sources[i].ID()] = d
+		}
+	}
+
+	return res
+}
+
+// ShortestPathTree returns the edges of the shortest path tree rooted at the node from, found by a
+// Dijkstra search traversing edges that satisfy the edge filter ef. Each node reachable from from,
+// other than from itself, contributes the edge leading into it on its shortest path, and edges are
+// ordered by the ID of that node. Unreachable nodes contribute no edge. If from does not exist in the
+// graph or an edge with a negative weight is encountered, nil is returned.
+func (g *Undirected) ShortestPathTree(from Node, ef EdgeFilter) []Edge {
+	_, pred, err := g.ShortestPaths(from, ef)
+	if err != nil {
+		return nil
+	}
+	var tree []Edge
+	for _, n := range g.nodes {
+		if n == nil {
+			continue
+		}
+		if e, ok := pred[n.ID()]; ok {
+			tree = append(tree, e)
+		}
+	}
+
+	return tree
+}
+
+// dijkstra performs a Dijkstra search from the node from, terminating early if to is not nil and
+// has been reached. Edge costs are given by cf, or by edge weights if cf is nil.
+func (g *Undirected) dijkstra(from, to Node, ef EdgeFilter, cf CostFunc) (dist map[int]float64, pred map[int]Edge, err error) {
+	if cf == nil {
+		cf = Edge.Weight
+	}
+	dist = map[int]float64{from.ID(): 0}
+	pred = make(map[int]Edge)
+
+	var done []bool
+	pq := &PriorityQueue{}
+	pq.Push(from, 0)
+	for pq.Len() > 0 {
+		u, d := pq.Pop()
+		if u == to {
+			break
+		}
+		done = mark(u, done)
+		for _, h := range u.Hops(ef) {
+			w := cf(h.Edge)
+			if w < 0 {
+				return nil, nil, NegativeWeight
+			}
+			if marked(h.Node, done) {
+				continue
+			}
+			id := h.Node.ID()
+			if od, ok := dist[id]; !ok || d+w < od {
+				dist[id] = d + w
+				pred[id] = h.Edge
+				pq.Push(h.Node, d+w)
+			}
+		}
+	}
+
+	return dist, pred, nil
+}
+
+// pathTo returns the path from the node from to the node to described by the predecessor edges in
+// pred, keyed by node ID.
+func pathTo(from, to Node, pred map[int]Edge) []Edge {
+	var path []Edge
+	for n := to; n != from; {
+		e := pred[n.ID()]
+		path = append(path, e)
+		n = adjacent(e, n)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+// BidirectionalSearch returns a path with the fewest edges from the node s to the node t, traversing
+// edges that satisfy the edge filter ef. Breadth-first searches are expanded a level at a time from
+// both s and t, always from the smaller frontier, until they meet, which on large sparse graphs visits
+// far fewer nodes than a single breadth-first search from s. If either node does not exist in the
+// graph an appropriate error is returned. If t cannot be reached from s, a not found error is returned.
+func (g *Undirected) BidirectionalSearch(s, t Node, ef EdgeFilter) (path []Edge, err error) {
+	for _, n := range [2]Node{s, t} {
+		ok, err := g.Has(n)
+		if !ok {
+			if err == nil {
+				err = NodeDoesNotExist
+			}
+			return nil, err
+		}
+	}
+	if s == t {
+		return nil, nil
+	}
+
+	type side struct {
+		frontier []Node
+		visits   []bool
+		dist     map[int]int
+		pred     map[int]Edge
+	}
+	var sides [2]side
+	for i, n := range [2]Node{s, t} {
+		sides[i] = side{
+			frontier: []Node{n},
+			visits:   mark(n, nil),
+			dist:     map[int]int{n.ID(): 0},
+			pred:     make(map[int]Edge),
+		}
+	}
+
+	for len(sides[0].frontier) > 0 && len(sides[1].frontier) > 0 {
+		a, b := &sides[0], &sides[1]
+		if len(b.frontier) < len(a.frontier) {
+			a, b = b, a
+		}
+
+		var (
+			meet Node
+			best int
+			next []Node
+		)
+		for _, u := range a.frontier {
+			for _, h := range u.Hops(ef) {
+				v := h.Node
+				if marked(v, a.visits) {
+					continue
+				}
+				a.visits = mark(v, a.visits)
+				a.dist[v.ID()] = a.dist[u.ID()] + 1
+				a.pred[v.ID()] = h.Edge
+				next = append(next, v)
+				if marked(v, b.visits) {
+					if d := a.dist[v.ID()] + b.dist[v.ID()]; meet == nil || d < best {
+						meet, best = v, d
+					}
+				}
+			}
+		}
+		a.frontier = next
+
+		if meet != nil {
+			path = pathTo(s, meet, sides[0].pred)
+			for n := meet; n != t; {
+				e := sides[1].pred[n.ID()]
+				path = append(path, e)
+				n = adjacent(e, n)
+			}
+			return path, nil
+		}
+	}
+
+	return nil, notFound
+}
+
+// KShortestPaths returns up to k loopless paths from the node s to the node t in order of increasing
+// cost, with their costs, traversing edges that satisfy the edge filter ef and using edge weights as
+// distances. Paths are found using Yen's algorithm. Paths of equal cost are ordered by the number of
+// edges they hold, then by the sequence of node IDs they visit and finally by the sequence of edge
+// IDs; to honour this, every path costing no more than the k-th path is found before the result is
+// ordered and truncated to k paths. If either node does not exist in the graph, t cannot be reached
+// from s or an edge with a negative weight is encountered, nil slices are returned.
+func (g *Undirected) KShortestPaths(s, t Node, k int, ef EdgeFilter) ([][]Edge, []float64) {
+	if k < 1 {
+		return nil, nil
+	}
+	path, cost, err := g.ShortestPath(s, t, ef)
+	if err != nil {
+		return nil, nil
+	}
+	found := kPaths{{path: path, nodes: pathNodeIDs(s, path), cost: cost}}
+	var pending kPaths
+	for {
+		prev, nodes := found[len(found)-1].path, found[len(found)-1].nodes
+		for i := range prev {
+			root := prev[:i]
+			spur := g.nodes[nodes[i]]
+
+			// Exclude the next edge of every known path sharing this root, and the root's nodes.
+			cut := make(map[Edge]struct{})
+			for _, p := range found {
+				if len(p.path) > i && sameEdges(p.path[:i], root) {
+					cut[p.path[i]] = struct{}{}
+				}
+			}
+			excl := make(map[int]struct{}, i)
+			for _, id := range nodes[:i] {
+				excl[id] = struct{}{}
+			}
+			spurFilter := func(e Edge) bool {
+				if _, ok := cut[e]; ok {
+					return false
+				}
+				u, v := e.Nodes()
+				if _, ok := excl[u.ID()]; ok {
+					return false
+				}
+				if _, ok := excl[v.ID()]; ok {
+					return false
+				}
+				return ef(e)
+			}
+
+			dist, pred, err := g.dijkstra(spur, t, spurFilter, nil)
+			if err != nil {
+				return nil, nil
+			}
+			if _, ok := dist[t.ID()]; !ok {
+				continue
+			}
+			p := append(append([]Edge(nil), root...), pathTo(spur, t, pred)...)
+			dup := false
+			for _, c := range pending {
+				if sameEdges(c.path, p) {
+					dup = true
+					break
+				}
+			}
+			if dup {
+				continue
+			}
+			var w float64
+			for _, e := range p {
+				w += e.Weight()
+			}
+			pending = append(pending, kPath{path: p, nodes: pathNodeIDs(s, p), cost: w})
+		}
+		if len(pending) == 0 {
+			break
+		}
+
+		best := 0
+		for i := range pending[1:] {
+			if pending.Less(i+1, best) {
+				best = i + 1
+			}
+		}
+		// Yen's algorithm yields paths in order of cost but not in order within a cost, so keep
+		// going until no remaining candidate can tie with the k-th path found.
+		if len(found) >= k && pending[best].cost > found[k-1].cost {
+			break
+		}
+		found = append(found, pending[best])
+		pending[best] = pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+	}
+
+	sort.Stable(found)
+	if len(found) > k {
+		found = found[:k]
+	}
+	paths := make([][]Edge, len(found))
+	costs := make([]float64, len(found))
+	for i, c := range found {
+		paths[i], costs[i] = c.path, c.cost
+	}
+
+	return paths, costs
+}
+
+// kPath is a candidate path considered by KShortestPaths.
+type kPath struct {
+	path  []Edge
+	nodes []int
+	cost  float64
+}
+
+// kPaths orders paths by cost, then by number of edges and then by lessPath.
+type kPaths []kPath
+
+func (p kPaths) Len() int      { return len(p) }
+func (p kPaths) Swap(i, j int) { p[i], p[j] = p[j], p[i] }
+func (p kPaths) Less(i, j int) bool {
+	a, b := p[i], p[j]
+	if a.cost != b.cost {
+		return a.cost < b.cost
+	}
+	if len(a.path) != len(b.path) {
+		return len(a.path) < len(b.path)
+	}
+	return lessPath(a.nodes, a.path, b.nodes, b.path)
+}
+
+// pathNodeIDs returns the IDs of the nodes visited by path, starting from the node from.
+func pathNodeIDs(from Node, path []Edge) []int {
+	ids := make([]int, 1, len(path)+1)
+	ids[0] = from.ID()
+	n := from
+	for _, e := range path {
+		n = adjacent(e, n)
+		ids = append(ids, n.ID())
+	}
+	return ids
+}
+
+// sameEdges returns whether a and b hold the same edges in the same order.
+func sameEdges(a, b []Edge) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// lessPath returns whether the path a, visiting the nodes with IDs an, orders before the path b,
+// visiting the nodes with IDs bn, by node IDs and then by edge IDs. The paths must be of equal length.
+func lessPath(an []int, a []Edge, bn []int, b []Edge) bool {
+	for i := range an {
+		if an[i] != bn[i] {
+			return an[i] < bn[i]
+		}
+	}
+	for i := range a {
+		if a[i].ID() != b[i].ID() {
+			return a[i].ID() < b[i].ID()
+		}
+	}
+	return false
+}
+
+// BellmanFord returns the shortest path distances from the node from to each node reachable from it
+// via edges that satisfy the edge filter ef, and the edge leading into each node on its shortest path,
+// keyed by node ID. Unlike ShortestPaths, negative edge weights are allowed. However, since an
+// undirected edge may be traversed in either direction, any reachable edge with a negative weight
+// forms a negative cycle by itself. If a negative cycle is reachable from from, negCycle is returned
+// true and dist and pred do not describe shortest paths. If from does not exist in the graph, dist and
+// pred are nil.
+func (g *Undirected) BellmanFord(from Node, ef EdgeFilter) (dist map[int]float64, pred map[int]Edge, negCycle bool) {
+	if ok, _ := g.Has(from); !ok {
+		return nil, nil, false
+	}
+
+	var edges []Edge
+	for _, e := range g.compEdges {
+		if ef(e) {
+			edges = append(edges, e)
+		}
+	}
+
+	dist = map[int]float64{from.ID(): 0}
+	pred = make(map[int]Edge)
+	relax := func(e Edge, u, v Node) bool {
+		du, ok := dist[u.ID()]
+		if !ok {
+			return false
+		}
+		if dv, ok := dist[v.ID()]; !ok || du+e.Weight() < dv {
+			dist[v.ID()] = du + e.Weight()
+			pred[v.ID()] = e
+			return true
+		}
+		return false
+	}
+
+	for i := 1; i < g.Order(); i++ {
+		changed := false
+		for _, e := range edges {
+			u, v := e.Nodes()
+			if relax(e, u, v) {
+				changed = true
+			}
+			if relax(e, v, u) {
+				changed = true
+			}
+		}
+		if !changed {
+			return dist, pred, false
+		}
+	}
+	for _, e := range edges {
+		u, v := e.Nodes()
+		if relax(e, u, v) || relax(e, v, u) {
+			return dist, pred, true
+		}
+	}
+
+	return dist, pred, false
+}
+
+// AllPairsShortestPaths returns the shortest path distances between all pairs of nodes in the graph
+// using the Floyd-Warshall algorithm, traversing edges that satisfy the edge filter ef and using edge
+// weights as distances. Edge weights are expected to be non-negative. The returned matrices are indexed
+// by node ID in [0, NextNodeID()). dist[u][v] holds the distance between nodes u and v, or +Inf if v
+// cannot be reached from u. next[u][v] holds the ID of the node following u on a shortest path from
+// u to v, or -1 if there is no such path, so the path from u to v is u, next[u][v], next[next[u][v]][v]
+// and so on until v is reached. IDs in the range that do not correspond to a node in the graph, for
+// example after a node deletion, have rows and columns of +Inf in dist and -1 in next.
+func (g *Undirected) AllPairsShortestPaths(ef EdgeFilter) (dist [][]float64, next [][]int) {
+	n := g.NextNodeID()
+	dist = make([][]float64, n)
+	next = make([][]int, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+		next[i] = make([]int, n)
+		for j := range dist[i] {
+			dist[i][j] = math.Inf(1)
+			next[i][j] = -1
+		}
+	}
+	for _, u := range g.compNodes {
+		id := u.ID()
+		dist[id][id] = 0
+		next[id][id] = id
+	}
+	for _, e := range g.compEdges {
+		if !ef(e) {
+			continue
+		}
+		u, v := e.Nodes()
+		uid, vid := u.ID(), v.ID()
+		if uid == vid {
+			continue
+		}
+		if w := e.Weight(); w < dist[uid][vid] {
+			dist[uid][vid], dist[vid][uid] = w, w
+			next[uid][vid], next[vid][uid] = vid, uid
+		}
+	}
+
+	for k := 0; k < n; k++ {
+		for i := 0; i < n; i++ {
+			if math.IsInf(dist[i][k], 1) {
+				continue
+			}
+			for j := 0; j < n; j++ {
+				if d := dist[i][k] + dist[k][j]; d < dist[i][j] {
+					dist[i][j] = d
+					next[i][j] = next[i][k]
+				}
+			}
+		}
+	}
+
+	return dist, next
+}
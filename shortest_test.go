@@ -0,0 +1,357 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+	"math/rand"
+)
+
+type we struct {
+	u, v int
+	w    float64
+}
+
+// Tests
+var (
+	wuv = []we{
+		{0, 1, 7},
+		{0, 2, 9},
+		{0, 5, 14},
+		{1, 2, 10},
+		{1, 3, 15},
+		{2, 3, 11},
+		{2, 5, 2},
+		{3, 4, 6},
+		{4, 5, 9},
+	}
+	wDists = []float64{0: 0, 1: 7, 2: 9, 3: 20, 4: 20, 5: 11}
+	wPath  = []int{0, 2, 5, 4}
+
+	all = func(_ Edge) bool { return true }
+)
+
+func weightedUndirected(c *check.C, edges []we) (g *Undirected) {
+	g = NewUndirected()
+	for _, e := range edges {
+		u, _ := g.AddID(e.u)
+		v, _ := g.AddID(e.v)
+		g.Connect(u, v, e.w, 0)
+	}
+
+	return
+}
+
+func pathNodes(from Node, path []Edge) []int {
+	ids := []int{from.ID()}
+	n := from
+	for _, e := range path {
+		n = adjacent(e, n)
+		ids = append(ids, n.ID())
+	}
+	return ids
+}
+
+func (s *S) TestShortestPath(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	path, cost, err := g.ShortestPath(g.Node(0), g.Node(4), all)
+	c.Assert(err, check.IsNil)
+	c.Check(cost, check.Equals, wDists[4])
+	c.Check(pathNodes(g.Node(0), path), check.DeepEquals, wPath)
+
+	dist, pred, err := g.ShortestPaths(g.Node(0), all)
+	c.Assert(err, check.IsNil)
+	for id, d := range wDists {
+		c.Check(dist[id], check.Equals, d)
+		var sum float64
+		for _, e := range pathTo(g.Node(0), g.Node(id), pred) {
+			sum += e.Weight()
+		}
+		c.Check(sum, check.Equals, d)
+	}
+}
+
+func (s *S) TestShortestPathCost(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	path, cost, err := g.ShortestPathCost(g.Node(0), g.Node(4), all, nil)
+	c.Assert(err, check.IsNil)
+	c.Check(cost, check.Equals, wDists[4])
+	c.Check(pathNodes(g.Node(0), path), check.DeepEquals, wPath)
+
+	// Flag the edges of the best path so that they cost ten times their weight.
+	for _, e := range path {
+		e.SetFlags(EdgeCut)
+	}
+	calls := 0
+	tiered := func(e Edge) float64 {
+		calls++
+		if e.Flags()&EdgeCut != 0 {
+			return 10 * e.Weight()
+		}
+		return e.Weight()
+	}
+	rerouted, cost, err := g.ShortestPathCost(g.Node(0), g.Node(4), all, tiered)
+	c.Assert(err, check.IsNil)
+	c.Check(calls > 0, check.Equals, true)
+	var sum float64
+	for _, e := range rerouted {
+		c.Check(e.Flags()&EdgeCut, check.Equals, EdgeFlags(0))
+		sum += e.Weight()
+	}
+	c.Check(cost, check.Equals, sum)
+	c.Check(cost > wDists[4], check.Equals, true)
+
+	// Weights are not altered.
+	path, _, err = g.ShortestPath(g.Node(0), g.Node(4), all)
+	c.Assert(err, check.IsNil)
+	c.Check(pathNodes(g.Node(0), path), check.DeepEquals, wPath)
+
+	_, _, err = g.ShortestPathCost(g.Node(0), g.Node(4), all, func(e Edge) float64 { return -1 })
+	c.Check(err, check.Equals, NegativeWeight)
+}
+
+func (s *S) TestShortestPathTree(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	g.AddID(20)
+	tree := g.ShortestPathTree(g.Node(0), all)
+	c.Check(tree, check.HasLen, len(wDists)-1)
+
+	// Walk from each node towards the root using only tree edges.
+	adj := make(map[int][]Edge)
+	for _, e := range tree {
+		u, v := e.Nodes()
+		adj[u.ID()] = append(adj[u.ID()], e)
+		adj[v.ID()] = append(adj[v.ID()], e)
+	}
+	var walk func(n Node, from Edge) (float64, bool)
+	walk = func(n Node, from Edge) (float64, bool) {
+		if n.ID() == 0 {
+			return 0, true
+		}
+		for _, e := range adj[n.ID()] {
+			if e == from {
+				continue
+			}
+			if d, ok := walk(adjacent(e, n), e); ok {
+				return d + e.Weight(), true
+			}
+		}
+		return 0, false
+	}
+	for id, d := range wDists {
+		sum, ok := walk(g.Node(id), nil)
+		c.Check(ok, check.Equals, true, check.Commentf("node %d", id))
+		c.Check(sum, check.Equals, d, check.Commentf("node %d", id))
+	}
+	c.Check(adj[20], check.HasLen, 0)
+
+	c.Check(g.ShortestPathTree(g.NewNode(), all), check.IsNil)
+}
+
+func (s *S) TestBFSLevels(c *check.C) {
+	const n = 6
+	g := path(c, n)
+	g.AddID(n)
+	levels := g.BFSLevels(g.Node(0), all)
+	c.Check(levels, check.HasLen, n)
+	for i := 0; i < n; i++ {
+		c.Check(levels[i], check.Equals, i)
+	}
+	_, ok := levels[n]
+	c.Check(ok, check.Equals, false)
+
+	c.Check(g.BFSLevels(g.Node(3), all), check.DeepEquals, map[int]int{0: 3, 1: 2, 2: 1, 3: 0, 4: 1, 5: 2})
+	c.Check(g.BFSLevels(g.Node(3), func(e Edge) bool { return e.Head().ID() != 1 && e.Tail().ID() != 1 }),
+		check.DeepEquals, map[int]int{2: 1, 3: 0, 4: 1, 5: 2})
+
+	w := weightedUndirected(c, wuv)
+	levels = w.BFSLevels(w.Node(0), all)
+	c.Check(levels, check.DeepEquals, map[int]int{0: 0, 1: 1, 2: 1, 5: 1, 3: 2, 4: 2})
+	c.Check(g.BFSLevels(g.NewNode(), all), check.IsNil)
+}
+
+func (s *S) TestMultiSourceShortestPaths(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	g.AddID(20)
+	sources := append(append([]Node(nil), g.Nodes()...), g.NewNode())
+	for _, threads := range []int{1, 2, 4, 100} {
+		res := g.MultiSourceShortestPaths(sources, threads)
+		c.Check(res, check.HasLen, g.Order())
+		for _, n := range g.Nodes() {
+			dist, _, err := g.ShortestPaths(n, all)
+			c.Assert(err, check.IsNil)
+			c.Check(res[n.ID()], check.DeepEquals, dist, check.Commentf("threads=%d source=%d", threads, n.ID()))
+		}
+	}
+	c.Check(g.MultiSourceShortestPaths(nil, 2), check.HasLen, 0)
+}
+
+func (s *S) TestKShortestPaths(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	paths, costs := g.KShortestPaths(g.Node(0), g.Node(4), 5, all)
+	c.Assert(len(paths), check.Equals, 5)
+	c.Assert(len(costs), check.Equals, 5)
+
+	path, cost, err := g.ShortestPath(g.Node(0), g.Node(4), all)
+	c.Assert(err, check.IsNil)
+	c.Check(paths[0], check.DeepEquals, path)
+	c.Check(costs[0], check.Equals, cost)
+	for i := 1; i < len(costs); i++ {
+		c.Check(costs[i] >= costs[i-1], check.Equals, true)
+	}
+
+	var nodes [][]int
+	for _, p := range paths {
+		nodes = append(nodes, pathNodes(g.Node(0), p))
+	}
+	c.Check(costs, check.DeepEquals, []float64{20, 23, 26, 28, 28})
+	c.Check(nodes, check.DeepEquals, [][]int{
+		{0, 2, 5, 4},
+		{0, 5, 4},
+		{0, 2, 3, 4},
+		{0, 1, 3, 4},
+		{0, 1, 2, 5, 4},
+	})
+
+	paths, _ = g.KShortestPaths(g.Node(0), g.Node(4), 100, all)
+	c.Check(len(paths) < 100, check.Equals, true)
+	for i, p := range paths {
+		seen := make(map[int]bool)
+		for _, id := range pathNodes(g.Node(0), p) {
+			c.Check(seen[id], check.Equals, false)
+			seen[id] = true
+		}
+		for _, q := range paths[:i] {
+			c.Check(sameEdges(p, q), check.Equals, false)
+		}
+	}
+}
+
+func (s *S) TestShortestPathFiltered(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	for _, e := range g.Edges() {
+		if u, v := e.Nodes(); u.ID() == 2 && v.ID() == 5 {
+			e.SetFlags(EdgeCut)
+		}
+	}
+	_, cost, err := g.ShortestPath(g.Node(0), g.Node(5), func(e Edge) bool { return e.Flags()&EdgeCut == 0 })
+	c.Assert(err, check.IsNil)
+	c.Check(cost, check.Equals, 14.)
+}
+
+func (s *S) TestShortestPathErrors(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	g.AddID(6)
+	_, cost, err := g.ShortestPath(g.Node(0), g.Node(6), all)
+	c.Check(err, check.Equals, notFound)
+	c.Check(math.IsInf(cost, 1), check.Equals, true)
+
+	g.Connect(g.Node(3), g.Node(6), -1, 0)
+	_, _, err = g.ShortestPaths(g.Node(0), all)
+	c.Check(err, check.Equals, NegativeWeight)
+}
+
+func (s *S) TestBellmanFord(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	dist, pred, neg := g.BellmanFord(g.Node(0), all)
+	c.Check(neg, check.Equals, false)
+	for id, d := range wDists {
+		c.Check(dist[id], check.Equals, d)
+		c.Check(len(pathTo(g.Node(0), g.Node(id), pred)) > 0 || id == 0, check.Equals, true)
+	}
+
+	g.AddID(6)
+	g.AddID(7)
+	e, _ := g.Connect(g.Node(6), g.Node(7), -1, 0)
+	_, _, neg = g.BellmanFord(g.Node(0), all)
+	c.Check(neg, check.Equals, false)
+	g.Connect(g.Node(4), g.Node(6), 1, 0)
+	_, _, neg = g.BellmanFord(g.Node(0), all)
+	c.Check(neg, check.Equals, true)
+	_, _, neg = g.BellmanFord(g.Node(0), func(f Edge) bool { return f != e })
+	c.Check(neg, check.Equals, false)
+}
+
+func randomUndirected(c *check.C, rnd *rand.Rand, n, m int) *Undirected {
+	g := NewUndirected()
+	for i := 0; i < n; i++ {
+		g.AddID(i)
+	}
+	for i := 0; i < m; i++ {
+		g.ConnectByID(rnd.Intn(n), rnd.Intn(n), float64(rnd.Intn(20)+1), 0)
+	}
+
+	return g
+}
+
+func (s *S) TestBidirectionalSearch(c *check.C) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		g := randomUndirected(c, rnd, 50, 60)
+		b := NewBreadthFirst()
+		for j := 0; j < 10; j++ {
+			u, v := g.Node(rnd.Intn(50)), g.Node(rnd.Intn(50))
+			want, werr := b.Path(u, v, all)
+			b.Reset()
+			path, err := g.BidirectionalSearch(u, v, all)
+			c.Check(err, check.Equals, werr)
+			c.Check(len(path), check.Equals, len(want))
+			if err == nil && len(path) > 0 {
+				ids := pathNodes(u, path)
+				c.Check(ids[len(ids)-1], check.Equals, v.ID())
+			}
+		}
+	}
+
+	g := grid(c, 4, 4, func(_, _ int) float64 { return 1 })
+	path, err := g.BidirectionalSearch(g.Node(0), g.Node(15), all)
+	c.Assert(err, check.IsNil)
+	c.Check(len(path), check.Equals, 6)
+}
+
+func (s *S) TestAllPairsShortestPaths(c *check.C) {
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 10; i++ {
+		g := randomUndirected(c, rnd, 20, 30)
+		g.DeleteByID(rnd.Intn(20))
+		dist, next := g.AllPairsShortestPaths(all)
+		for _, u := range g.Nodes() {
+			sd, _, err := g.ShortestPaths(u, all)
+			c.Assert(err, check.IsNil)
+			for v := range dist[u.ID()] {
+				d, ok := sd[v]
+				if !ok {
+					c.Check(math.IsInf(dist[u.ID()][v], 1), check.Equals, true)
+					c.Check(next[u.ID()][v], check.Equals, -1)
+					continue
+				}
+				c.Check(dist[u.ID()][v], check.Equals, d)
+
+				var sum float64
+				for k := u.ID(); k != v; k = next[k][v] {
+					w := math.Inf(1)
+					ce, _ := g.ConnectingEdges(g.Node(k), g.Node(next[k][v]))
+					for _, e := range ce {
+						w = math.Min(w, e.Weight())
+					}
+					sum += w
+				}
+				c.Check(sum, check.Equals, d)
+			}
+		}
+	}
+}
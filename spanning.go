@@ -0,0 +1,193 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"math"
+	"sort"
+)
+
+// MinimumSpanningTree returns the edges of a minimum spanning tree of the graph and the sum of their
+// weights, determined using Kruskal's algorithm. If the graph is not connected a minimum spanning
+// forest is returned, with a tree for each connected component, and weight is the sum over all the
+// edges of the forest.
+func (g *Undirected) MinimumSpanningTree() (tree []Edge, weight float64) {
+	edges := make(edgesByWeight, len(g.compEdges))
+	copy(edges, g.compEdges)
+	sort.Stable(edges)
+
+	ds := newDisjointSet(g.NextNodeID())
+	for _, e := range edges {
+		if ds.union(e.Head().ID(), e.Tail().ID()) {
+			tree = append(tree, e)
+			weight += e.Weight()
+		}
+	}
+
+	return
+}
+
+// MinimumSpanningTreePrim returns the edges of a minimum spanning tree of the connected component
+// of the graph holding the node start and the sum of their weights, determined using Prim's algorithm.
+// Nodes not reachable from start are not included in the tree. Unlike a Selector, which makes weighted
+// random choices, the tree is grown by always choosing the lowest weight edge leaving it, so an
+// internal min-priority queue is used. If start does not exist in the graph, tree is nil.
+func (g *Undirected) MinimumSpanningTreePrim(start Node) (tree []Edge, weight float64) {
+	if ok, _ := g.Has(start); !ok {
+		return nil, 0
+	}
+
+	var done []bool
+	best := make(map[int]Edge)
+	pq := &PriorityQueue{}
+	pq.Push(start, 0)
+	for pq.Len() > 0 {
+		u, w := pq.Pop()
+		done = mark(u, done)
+		if e, ok := best[u.ID()]; ok {
+			tree = append(tree, e)
+			weight += w
+		}
+		for _, h := range u.Hops(func(_ Edge) bool { return true }) {
+			if marked(h.Node, done) {
+				continue
+			}
+			id := h.Node.ID()
+			if e, ok := best[id]; !ok || h.Edge.Weight() < e.Weight() {
+				best[id] = h.Edge
+				pq.Push(h.Node, h.Edge.Weight())
+			}
+		}
+	}
+
+	return
+}
+
+// SpanningForest returns the edges of a spanning forest of the graph, with a tree for each connected
+// component, and the number of components. Unlike MinimumSpanningTree, edge weights are ignored and
+// edges are considered in the order given by Edges. The forest is found using union-find.
+func (g *Undirected) SpanningForest() (forest []Edge, components int) {
+	ds := newDisjointSet(g.NextNodeID())
+	for _, e := range g.compEdges {
+		if ds.union(e.Head().ID(), e.Tail().ID()) {
+			forest = append(forest, e)
+		}
+	}
+
+	return forest, len(g.compNodes) - len(forest)
+}
+
+// MinimaxPath returns a path from the node s to the node t, traversing edges that satisfy the edge
+// filter ef, that minimizes the largest weight of its edges, and that weight, the bottleneck of the
+// path. The path lies on a minimum spanning forest of the edges satisfying ef, found using Kruskal's
+// algorithm, which stops as soon as s and t are joined. If s and t are the same node, the path is
+// empty and the bottleneck is -Inf. If either node does not exist in the graph an appropriate error
+// is returned. If t cannot be reached from s, the bottleneck is +Inf and a not found error is returned.
+func (g *Undirected) MinimaxPath(s, t Node, ef EdgeFilter) (path []Edge, bottleneck float64, err error) {
+	return g.bottleneckPath(s, t, ef, false)
+}
+
+// MaximinPath returns a path from the node s to the node t, traversing edges that satisfy the edge
+// filter ef, that maximizes the smallest weight of its edges, and that weight, the width of the path.
+// This is the widest path problem. The path lies on a maximum spanning forest of the edges satisfying
+// ef. If s and t are the same node, the path is empty and the width is +Inf. If either node does not
+// exist in the graph an appropriate error is returned. If t cannot be reached from s, the width is
+// -Inf and a not found error is returned.
+func (g *Undirected) MaximinPath(s, t Node, ef EdgeFilter) (path []Edge, width float64, err error) {
+	return g.bottleneckPath(s, t, ef, true)
+}
+
+// bottleneckPath returns the minimax path, or the maximin path if widest is true, from s to t.
+func (g *Undirected) bottleneckPath(s, t Node, ef EdgeFilter, widest bool) (path []Edge, bottleneck float64, err error) {
+	none, empty := math.Inf(1), math.Inf(-1)
+	if widest {
+		none, empty = empty, none
+	}
+	for _, n := range [2]Node{s, t} {
+		ok, err := g.Has(n)
+		if !ok {
+			if err == nil {
+				err = NodeDoesNotExist
+			}
+			return nil, none, err
+		}
+	}
+	if s == t {
+		return nil, empty, nil
+	}
+
+	var edges edgesByWeight
+	for _, e := range g.compEdges {
+		if ef(e) {
+			edges = append(edges, e)
+		}
+	}
+	if widest {
+		sort.Stable(sort.Reverse(edges))
+	} else {
+		sort.Stable(edges)
+	}
+
+	// Grow the spanning forest until s and t are in the same tree.
+	ds := newDisjointSet(g.NextNodeID())
+	forest := make(map[int][]*Hop)
+	for _, e := range edges {
+		u, v := e.Nodes()
+		if !ds.union(u.ID(), v.ID()) {
+			continue
+		}
+		forest[u.ID()] = append(forest[u.ID()], &Hop{Edge: e, Node: v})
+		forest[v.ID()] = append(forest[v.ID()], &Hop{Edge: e, Node: u})
+		if ds.find(s.ID()) == ds.find(t.ID()) {
+			break
+		}
+	}
+	if ds.find(s.ID()) != ds.find(t.ID()) {
+		return nil, none, notFound
+	}
+
+	// The tree path from s to t is unique.
+	pred := make(map[int]Edge)
+	q := &queue{}
+	q.Enqueue(s)
+	for q.Len() > 0 {
+		u, _ := q.Dequeue()
+		if u == t {
+			break
+		}
+		for _, h := range forest[u.ID()] {
+			if _, ok := pred[h.Node.ID()]; !ok && h.Node != s {
+				pred[h.Node.ID()] = h.Edge
+				q.Enqueue(h.Node)
+			}
+		}
+	}
+	path = pathTo(s, t, pred)
+	bottleneck = empty
+	for _, e := range path {
+		if w := e.Weight(); widest && w < bottleneck || !widest && w > bottleneck {
+			bottleneck = w
+		}
+	}
+
+	return path, bottleneck, nil
+}
+
+type edgesByWeight []Edge
+
+func (e edgesByWeight) Len() int           { return len(e) }
+func (e edgesByWeight) Less(i, j int) bool { return e[i].Weight() < e[j].Weight() }
+func (e edgesByWeight) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
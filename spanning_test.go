@@ -0,0 +1,153 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+	"math"
+	"math/rand"
+)
+
+// Tests
+func (s *S) TestMinimumSpanningTree(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	tree, w := g.MinimumSpanningTree()
+	c.Check(len(tree), check.Equals, g.Order()-1)
+	c.Check(w, check.Equals, 33.)
+
+	g.AddID(6)
+	g.AddID(7)
+	g.ConnectByID(6, 7, 3, 0)
+	g.ConnectByID(6, 6, 1, 0)
+	tree, w = g.MinimumSpanningTree()
+	c.Check(len(tree), check.Equals, g.Order()-2)
+	c.Check(w, check.Equals, 36.)
+	inTree := make(map[Edge]bool)
+	for _, e := range tree {
+		inTree[e] = true
+	}
+	c.Check(len(g.ConnectedComponents(func(e Edge) bool { return inTree[e] })), check.Equals, 2)
+}
+
+func (s *S) TestMinimumSpanningTreePrim(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	tree, w := g.MinimumSpanningTreePrim(g.Node(3))
+	c.Check(len(tree), check.Equals, g.Order()-1)
+	c.Check(w, check.Equals, 33.)
+
+	g.AddID(6)
+	g.AddID(7)
+	g.ConnectByID(6, 7, 3, 0)
+	tree, w = g.MinimumSpanningTreePrim(g.Node(7))
+	c.Check(len(tree), check.Equals, 1)
+	c.Check(w, check.Equals, 3.)
+
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20; i++ {
+		g := randomUndirected(c, rnd, 30, 120)
+		if !g.IsConnected(all) {
+			continue
+		}
+		_, kw := g.MinimumSpanningTree()
+		pt, pw := g.MinimumSpanningTreePrim(g.Nodes()[rnd.Intn(g.Order())])
+		c.Check(len(pt), check.Equals, g.Order()-1)
+		c.Check(pw, check.Equals, kw)
+	}
+}
+
+func (s *S) TestSpanningForest(c *check.C) {
+	g := undirected(c, uv)
+	g.ConnectByID(1, 1, 1, 0)
+	g.ConnectByID(1, 4, 1, 0)
+	forest, n := g.SpanningForest()
+	c.Check(n, check.Equals, 1)
+	c.Check(len(forest), check.Equals, g.Order()-n)
+
+	g.DeleteByID(deleteNode)
+	g.AddID(20)
+	forest, n = g.SpanningForest()
+	c.Check(n, check.Equals, len(g.ConnectedComponents(all)))
+	c.Check(len(forest), check.Equals, g.Order()-n)
+	ds := newDisjointSet(g.NextNodeID())
+	for _, e := range forest {
+		c.Check(ds.union(e.Head().ID(), e.Tail().ID()), check.Equals, true)
+	}
+}
+
+func (s *S) TestMinimaxPath(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	g.AddID(10)
+	tree, _ := g.MinimumSpanningTree()
+	mst := NewUndirected()
+	for _, e := range tree {
+		u, _ := mst.AddID(e.Head().ID())
+		v, _ := mst.AddID(e.Tail().ID())
+		mst.Connect(u, v, e.Weight(), 0)
+	}
+
+	for _, u := range g.Nodes() {
+		for _, v := range g.Nodes() {
+			if u.ID() == 10 || v.ID() == 10 || u == v {
+				continue
+			}
+			path, bottleneck, err := g.MinimaxPath(u, v, all)
+			c.Assert(err, check.IsNil)
+			c.Check(pathNodes(u, path)[len(path)], check.Equals, v.ID())
+
+			// The bottleneck is the largest edge on the path between u and v in the minimum spanning tree.
+			tp := mst.AllSimplePaths(mst.Node(u.ID()), mst.Node(v.ID()), -1, all)
+			c.Assert(tp, check.HasLen, 1)
+			max := math.Inf(-1)
+			for _, e := range tp[0] {
+				max = math.Max(max, e.Weight())
+			}
+			c.Check(bottleneck, check.Equals, max, check.Commentf("%d-%d", u.ID(), v.ID()))
+
+			// No simple path has a smaller largest edge or a larger smallest edge.
+			_, width, err := g.MaximinPath(u, v, all)
+			c.Assert(err, check.IsNil)
+			for _, p := range g.AllSimplePaths(u, v, -1, all) {
+				lo, hi := math.Inf(1), math.Inf(-1)
+				for _, e := range p {
+					lo, hi = math.Min(lo, e.Weight()), math.Max(hi, e.Weight())
+				}
+				c.Check(hi >= bottleneck, check.Equals, true)
+				c.Check(lo <= width, check.Equals, true)
+			}
+		}
+	}
+
+	path, width, err := g.MaximinPath(g.Node(0), g.Node(4), all)
+	c.Assert(err, check.IsNil)
+	c.Check(width, check.Equals, 9.)
+	c.Check(pathNodes(g.Node(0), path), check.DeepEquals, []int{0, 5, 4})
+	path, bottleneck, err := g.MinimaxPath(g.Node(0), g.Node(4), all)
+	c.Assert(err, check.IsNil)
+	c.Check(bottleneck, check.Equals, 9.)
+
+	_, bottleneck, err = g.MinimaxPath(g.Node(0), g.Node(10), all)
+	c.Check(err, check.Equals, notFound)
+	c.Check(bottleneck, check.Equals, math.Inf(1))
+	_, width, err = g.MaximinPath(g.Node(0), g.Node(10), all)
+	c.Check(err, check.Equals, notFound)
+	c.Check(width, check.Equals, math.Inf(-1))
+	path, bottleneck, err = g.MinimaxPath(g.Node(0), g.Node(0), all)
+	c.Check(err, check.IsNil)
+	c.Check(path, check.HasLen, 0)
+	c.Check(bottleneck, check.Equals, math.Inf(-1))
+	_, _, err = g.MinimaxPath(g.Node(0), g.NewNode(), all)
+	c.Check(err, check.NotNil)
+}
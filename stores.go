@@ -17,6 +17,7 @@ package graph
 
 import (
 	"errors"
+	"sync/atomic"
 )
 
 var (
@@ -102,3 +103,201 @@ func (s *stack) Clear() {
 }
 
 func (s *stack) Len() int { return len(s.data) }
+
+// A PriorityQueue is a binary heap backed min-priority queue of nodes, keyed by node ID. Each node
+// may be held in the queue at most once; pushing a node already in the queue alters its priority. The
+// zero value is an empty queue ready to use.
+type PriorityQueue struct {
+	data []pqItem
+	pos  map[int]int
+}
+
+type pqItem struct {
+	node     Node
+	priority float64
+}
+
+// Push adds the node n to the queue with priority p. If a node with the same ID is already in the
+// queue, its priority is set to p instead, as for Update.
+func (pq *PriorityQueue) Push(n Node, p float64) {
+	if pq.pos == nil {
+		pq.pos = make(map[int]int)
+	}
+	if pq.Update(n, p) {
+		return
+	}
+	pq.data = append(pq.data, pqItem{node: n, priority: p})
+	pq.pos[n.ID()] = len(pq.data) - 1
+	pq.up(len(pq.data) - 1)
+}
+
+// Update sets the priority of the node in the queue with the same ID as n to p, which may be higher
+// or lower than its current priority, and returns true. If no such node is in the queue, the queue is
+// not altered and false is returned.
+func (pq *PriorityQueue) Update(n Node, p float64) bool {
+	i, ok := pq.pos[n.ID()]
+	if !ok {
+		return false
+	}
+	pq.data[i].priority = p
+	pq.fix(i)
+	return true
+}
+
+// Pop removes and returns the node with the lowest priority, and its priority. Nodes of equal
+// priority are returned in an unspecified order. If the queue is empty, a nil node is returned.
+func (pq *PriorityQueue) Pop() (Node, float64) {
+	if len(pq.data) == 0 {
+		return nil, 0
+	}
+	top := pq.data[0]
+	last := len(pq.data) - 1
+	pq.swap(0, last)
+	pq.data[last] = pqItem{}
+	pq.data = pq.data[:last]
+	delete(pq.pos, top.node.ID())
+	if len(pq.data) > 0 {
+		pq.down(0)
+	}
+
+	return top.node, top.priority
+}
+
+// Clear removes all nodes from the queue.
+func (pq *PriorityQueue) Clear() {
+	for i := range pq.data {
+		pq.data[i] = pqItem{}
+	}
+	pq.data = pq.data[:0]
+	pq.pos = nil
+}
+
+// Len returns the number of nodes in the queue.
+func (pq *PriorityQueue) Len() int { return len(pq.data) }
+
+func (pq *PriorityQueue) fix(i int) {
+	if !pq.up(i) {
+		pq.down(i)
+	}
+}
+
+func (pq *PriorityQueue) up(i int) (moved bool) {
+	for i > 0 {
+		p := (i - 1) / 2
+		if pq.data[p].priority <= pq.data[i].priority {
+			break
+		}
+		pq.swap(i, p)
+		i = p
+		moved = true
+	}
+	return
+}
+
+func (pq *PriorityQueue) down(i int) {
+	for {
+		l := 2*i + 1
+		if l >= len(pq.data) {
+			return
+		}
+		m := l
+		if r := l + 1; r < len(pq.data) && pq.data[r].priority < pq.data[l].priority {
+			m = r
+		}
+		if pq.data[i].priority <= pq.data[m].priority {
+			return
+		}
+		pq.swap(i, m)
+		i = m
+	}
+}
+
+func (pq *PriorityQueue) swap(i, j int) {
+	pq.data[i], pq.data[j] = pq.data[j], pq.data[i]
+	pq.pos[pq.data[i].node.ID()] = i
+	pq.pos[pq.data[j].node.ID()] = j
+}
+
+// disjointSet is a union-find structure over a set of integer IDs.
+type disjointSet struct {
+	parent, rank []int
+}
+
+func newDisjointSet(n int) *disjointSet {
+	s := &disjointSet{
+		parent: make([]int, n),
+		rank:   make([]int, n),
+	}
+	for i := range s.parent {
+		s.parent[i] = i
+	}
+	return s
+}
+
+func (s *disjointSet) find(x int) int {
+	for s.parent[x] != x {
+		s.parent[x] = s.parent[s.parent[x]]
+		x = s.parent[x]
+	}
+	return x
+}
+
+// union merges the sets holding x and y, returning false if they were already the same set.
+func (s *disjointSet) union(x, y int) bool {
+	x, y = s.find(x), s.find(y)
+	if x == y {
+		return false
+	}
+	switch {
+	case s.rank[x] < s.rank[y]:
+		s.parent[x] = y
+	case s.rank[x] > s.rank[y]:
+		s.parent[y] = x
+	default:
+		s.parent[y] = x
+		s.rank[x]++
+	}
+	return true
+}
+
+// concurrentDisjointSet is a union-find structure over a set of integer IDs that is safe for
+// concurrent use. Sets are linked by ID, the larger root below the smaller, so that concurrent
+// unions cannot form a cycle.
+type concurrentDisjointSet struct {
+	parent []int64
+}
+
+func newConcurrentDisjointSet(n int) *concurrentDisjointSet {
+	s := &concurrentDisjointSet{parent: make([]int64, n)}
+	for i := range s.parent {
+		s.parent[i] = int64(i)
+	}
+	return s
+}
+
+func (s *concurrentDisjointSet) find(x int) int {
+	for {
+		p := atomic.LoadInt64(&s.parent[x])
+		if p == int64(x) {
+			return x
+		}
+		gp := atomic.LoadInt64(&s.parent[p])
+		atomic.CompareAndSwapInt64(&s.parent[x], p, gp)
+		x = int(gp)
+	}
+}
+
+func (s *concurrentDisjointSet) union(x, y int) {
+	for {
+		x, y = s.find(x), s.find(y)
+		switch {
+		case x == y:
+			return
+		case x > y:
+			x, y = y, x
+		}
+		if atomic.CompareAndSwapInt64(&s.parent[y], int64(y), int64(x)) {
+			return
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+	"math/rand"
+)
+
+// Tests
+func (s *S) TestPriorityQueue(c *check.C) {
+	const n = 100
+	rnd := rand.New(rand.NewSource(1))
+	pq := &PriorityQueue{}
+	for i := 0; i < n; i++ {
+		pq.Push(newNode(i), rnd.Float64())
+	}
+	c.Check(pq.Len(), check.Equals, n)
+	last := -1.
+	for pq.Len() > 0 {
+		_, p := pq.Pop()
+		c.Check(p >= last, check.Equals, true)
+		last = p
+	}
+	nd, p := pq.Pop()
+	c.Check(nd, check.IsNil)
+	c.Check(p, check.Equals, 0.)
+}
+
+func (s *S) TestPriorityQueueUpdate(c *check.C) {
+	pq := &PriorityQueue{}
+	nodes := make([]Node, 5)
+	for i := range nodes {
+		nodes[i] = newNode(i)
+		pq.Push(nodes[i], float64(10*(i+1)))
+	}
+
+	c.Check(pq.Update(nodes[4], 5), check.Equals, true)
+	c.Check(pq.Update(nodes[0], 45), check.Equals, true)
+	c.Check(pq.Update(newNode(7), 1), check.Equals, false)
+	// Pushing a node already in the queue alters its priority.
+	pq.Push(nodes[2], 15)
+	c.Check(pq.Len(), check.Equals, 5)
+
+	var ids []int
+	var ps []float64
+	for pq.Len() > 0 {
+		nd, p := pq.Pop()
+		ids = append(ids, nd.ID())
+		ps = append(ps, p)
+	}
+	c.Check(ids, check.DeepEquals, []int{4, 2, 1, 3, 0})
+	c.Check(ps, check.DeepEquals, []float64{5, 15, 20, 40, 45})
+
+	pq.Push(nodes[0], 1)
+	pq.Clear()
+	c.Check(pq.Len(), check.Equals, 0)
+	c.Check(pq.Update(nodes[0], 2), check.Equals, false)
+}
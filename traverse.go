@@ -29,6 +29,9 @@ type Visit func(u, v Node)
 type BreadthFirst struct {
 	q      *queue
 	visits []bool
+	bits   bitset
+	useBit bool
+	pred   map[int]Edge
 }
 
 // NewBreadthFirst creates a new BreadthFirst searcher.
@@ -36,6 +39,13 @@ func NewBreadthFirst() *BreadthFirst {
 	return &BreadthFirst{q: &queue{}}
 }
 
+// NewBreadthFirstSize creates a new BreadthFirst searcher with its queue and visited list
+// preallocated to hold n nodes, avoiding repeated reallocation when searching large graphs. The
+// searcher is otherwise the same as one created by NewBreadthFirst.
+func NewBreadthFirstSize(n int) *BreadthFirst {
+	return &BreadthFirst{q: &queue{data: make([]Node, 0, n)}, visits: make([]bool, n)}
+}
+
 // Search searches a graph starting from node s until the NodeFilter function nf returns a value of
 // true, traversing edges in the graph that allow the Edgefilter function ef to return true. On success
 // the terminating node, t is returned. If vo is not nil, it is called with the start and end nodes of an
@@ -43,7 +53,7 @@ func NewBreadthFirst() *BreadthFirst {
 // is returned.
 func (b *BreadthFirst) Search(s Node, ef EdgeFilter, nf NodeFilter, vo Visit) (Node, error) {
 	b.q.Enqueue(s)
-	b.visits = mark(s, b.visits)
+	b.mark(s)
 	for b.q.Len() > 0 {
 		t, err := b.q.Dequeue()
 		if err != nil {
@@ -57,7 +67,7 @@ func (b *BreadthFirst) Search(s Node, ef EdgeFilter, nf NodeFilter, vo Visit) (N
 				if vo != nil {
 					vo(t, n)
 				}
-				b.visits = mark(n, b.visits)
+				b.mark(n)
 				b.q.Enqueue(n)
 			}
 		}
@@ -66,8 +76,41 @@ func (b *BreadthFirst) Search(s Node, ef EdgeFilter, nf NodeFilter, vo Visit) (N
 	return nil, notFound
 }
 
-// Visited marks the node n as having been visited by the sercher.
+// Path returns a path of edges from node s to node t with the fewest edges, traversing edges in the
+// graph that allow the EdgeFilter function ef to return true. The searcher is reset before the search
+// begins, and the nodes visited are retained until the next call to Path or Reset. If t cannot be
+// reached from s, an error is returned.
+func (b *BreadthFirst) Path(s, t Node, ef EdgeFilter) (path []Edge, err error) {
+	b.Reset()
+	b.pred = make(map[int]Edge)
+	defer b.q.Clear()
+	b.q.Enqueue(s)
+	b.mark(s)
+	for b.q.Len() > 0 {
+		u, err := b.q.Dequeue()
+		if err != nil {
+			return nil, err
+		}
+		if u == t {
+			return pathTo(s, t, b.pred), nil
+		}
+		for _, h := range u.Hops(ef) {
+			if !b.Visited(h.Node) {
+				b.pred[h.Node.ID()] = h.Edge
+				b.mark(h.Node)
+				b.q.Enqueue(h.Node)
+			}
+		}
+	}
+
+	return nil, notFound
+}
+
+// Visited returns whether the node n has been visited by the searcher.
 func (b *BreadthFirst) Visited(n Node) bool {
+	if b.useBit {
+		return b.bits.marked(n)
+	}
 	id := n.ID()
 	if id < 0 || id >= len(b.visits) {
 		return false
@@ -75,10 +118,47 @@ func (b *BreadthFirst) Visited(n Node) bool {
 	return b.visits[id]
 }
 
-// Reset clears the search queue and visited list.
+// UseBitset sets whether the searcher records visited nodes in a packed bitset, using one bit per
+// node ID rather than one byte, which reduces memory use on graphs with large ID ranges at a small
+// cost in speed. Nodes already visited are retained when the store is switched.
+func (b *BreadthFirst) UseBitset(on bool) {
+	if on == b.useBit {
+		return
+	}
+	if on {
+		b.bits = b.bits[:0]
+		for id, ok := range b.visits {
+			if ok {
+				b.bits = b.bits.set(id)
+			}
+		}
+		b.visits = nil
+	} else {
+		b.visits = b.visits[:0]
+		for id := 0; id < len(b.bits)*64; id++ {
+			if b.bits.isSet(id) {
+				b.visits = markID(id, b.visits)
+			}
+		}
+		b.bits = nil
+	}
+	b.useBit = on
+}
+
+func (b *BreadthFirst) mark(n Node) {
+	if b.useBit {
+		b.bits = b.bits.set(n.ID())
+		return
+	}
+	b.visits = mark(n, b.visits)
+}
+
+// Reset clears the search queue, visited list and recorded predecessors.
 func (b *BreadthFirst) Reset() {
 	b.q.Clear()
 	b.visits = b.visits[:0]
+	b.bits = b.bits[:0]
+	b.pred = nil
 }
 
 // DepthFirst is a type that can perform a depth-first search on a graph.
@@ -92,6 +172,13 @@ func NewDepthFirst() *DepthFirst {
 	return &DepthFirst{s: &stack{}}
 }
 
+// NewDepthFirstSize creates a new DepthFirst searcher with its stack and visited list preallocated
+// to hold n nodes, avoiding repeated reallocation when searching large graphs. The searcher is
+// otherwise the same as one created by NewDepthFirst.
+func NewDepthFirstSize(n int) *DepthFirst {
+	return &DepthFirst{s: &stack{data: make([]Node, 0, n)}, visits: make([]bool, n)}
+}
+
 // Search searches a graph starting from node s until the NodeFilter function nf returns a value of
 // true, traversing edges in the graph that allow the Edgefilter function ef to return true. On success
 // the terminating node, t is returned. If vo is not nil, it is called with the start and end nodes of an
@@ -122,7 +209,7 @@ func (d *DepthFirst) Search(s Node, ef EdgeFilter, nf NodeFilter, vo Visit) (Nod
 	return nil, notFound
 }
 
-// Visited marks the node n as having been visited by the sercher.
+// Visited returns whether the node n has been visited by the searcher.
 func (d *DepthFirst) Visited(n Node) bool {
 	id := n.ID()
 	if id < 0 || id >= len(d.visits) {
@@ -138,7 +225,10 @@ func (d *DepthFirst) Reset() {
 }
 
 func mark(n Node, v []bool) []bool {
-	id := n.ID()
+	return markID(n.ID(), v)
+}
+
+func markID(id int, v []bool) []bool {
 	if id == len(v) {
 		v = append(v, true)
 	} else if id > len(v) {
@@ -152,3 +242,107 @@ func mark(n Node, v []bool) []bool {
 
 	return v
 }
+
+func marked(n Node, v []bool) bool {
+	id := n.ID()
+	return id >= 0 && id < len(v) && v[id]
+}
+
+// bitset is a packed set of node IDs.
+type bitset []uint64
+
+func (s bitset) set(id int) bitset {
+	w := id / 64
+	switch {
+	case w < len(s):
+	case w < cap(s):
+		l := len(s)
+		s = s[:w+1]
+		for i := l; i < len(s); i++ {
+			s[i] = 0
+		}
+	default:
+		t := make(bitset, w+1, 2*(w+1))
+		copy(t, s)
+		s = t
+	}
+	s[w] |= 1 << uint(id%64)
+	return s
+}
+
+func (s bitset) isSet(id int) bool {
+	w := id / 64
+	return id >= 0 && w < len(s) && s[w]&(1<<uint(id%64)) != 0
+}
+
+func (s bitset) marked(n Node) bool { return s.isSet(n.ID()) }
+
+// Heuristic is a function type that returns an estimate of the cost of reaching a target from the
+// node n.
+type Heuristic func(n Node) float64
+
+// AStar is a type that can perform an A* search on a graph.
+type AStar struct {
+	pq     *PriorityQueue
+	visits []bool
+}
+
+// NewAStar creates a new AStar searcher.
+func NewAStar() *AStar {
+	return &AStar{pq: &PriorityQueue{}}
+}
+
+// Search searches a graph starting from node s until the NodeFilter function nf returns a value of
+// true, traversing edges in the graph that allow the Edgefilter function ef to return true and using
+// edge weights as costs. Nodes are expanded in order of their cost from s plus the estimated cost to
+// a target given by the Heuristic h. On success the terminating node, t, and the path of edges from s
+// to t are returned. If h is admissible and consistent, that is it never overestimates the remaining
+// cost and does not decrease by more than the weight of any edge traversed, the path returned is a
+// lowest cost path. A nil h reduces the search to Dijkstra's algorithm. If an edge with a negative
+// weight is encountered the error NegativeWeight is returned. If no node is found that satisfies nf,
+// an error is returned.
+func (a *AStar) Search(s Node, h Heuristic, ef EdgeFilter, nf NodeFilter) (Node, []Edge, error) {
+	if h == nil {
+		h = func(_ Node) float64 { return 0 }
+	}
+	cost := map[int]float64{s.ID(): 0}
+	pred := make(map[int]Edge)
+	a.pq.Push(s, h(s))
+	for a.pq.Len() > 0 {
+		t, _ := a.pq.Pop()
+		a.visits = mark(t, a.visits)
+		if nf(t) {
+			a.pq.Clear()
+			return t, pathTo(s, t, pred), nil
+		}
+		for _, hop := range t.Hops(ef) {
+			w := hop.Edge.Weight()
+			if w < 0 {
+				a.pq.Clear()
+				return nil, nil, NegativeWeight
+			}
+			if a.Visited(hop.Node) {
+				continue
+			}
+			id := hop.Node.ID()
+			if c, ok := cost[id]; !ok || cost[t.ID()]+w < c {
+				cost[id] = cost[t.ID()] + w
+				pred[id] = hop.Edge
+				a.pq.Push(hop.Node, cost[id]+h(hop.Node))
+			}
+		}
+	}
+
+	return nil, nil, notFound
+}
+
+// Visited returns whether the node n has been visited by the searcher.
+func (a *AStar) Visited(n Node) bool {
+	return marked(n, a.visits)
+}
+
+// Reset clears the search queue and visited list.
+func (a *AStar) Reset() {
+	a.pq.Clear()
+	a.visits = a.visits[:0]
+}
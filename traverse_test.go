@@ -0,0 +1,253 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program. If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+	"testing"
+)
+
+// Helpers
+func grid(c *check.C, rows, cols int, weight func(r, c int) float64) *Undirected {
+	g := NewUndirected()
+	for r := 0; r < rows; r++ {
+		for k := 0; k < cols; k++ {
+			g.AddID(r*cols + k)
+		}
+	}
+	for r := 0; r < rows; r++ {
+		for k := 0; k < cols; k++ {
+			if k+1 < cols {
+				g.ConnectByID(r*cols+k, r*cols+k+1, weight(r, k), 0)
+			}
+			if r+1 < rows {
+				g.ConnectByID(r*cols+k, (r+1)*cols+k, weight(r, k), 0)
+			}
+		}
+	}
+
+	return g
+}
+
+// Tests
+var (
+	_ func(*BreadthFirst, Node, EdgeFilter, NodeFilter, Visit) (Node, error) = (*BreadthFirst).Search
+	_ func(*DepthFirst, Node, EdgeFilter, NodeFilter, Visit) (Node, error)   = (*DepthFirst).Search
+)
+
+func (s *S) TestBreadthFirst(c *check.C) {
+	g := grid(c, 3, 3, func(_, _ int) float64 { return 1 })
+	var order []int
+	var visits [][2]int
+	b := NewBreadthFirst()
+	n, err := b.Search(g.Node(0), all, func(n Node) bool { order = append(order, n.ID()); return n.ID() == 8 }, func(u, v Node) {
+		visits = append(visits, [2]int{u.ID(), v.ID()})
+	})
+	c.Assert(err, check.IsNil)
+	c.Check(n, check.Equals, g.Node(8))
+	c.Check(order, check.DeepEquals, []int{0, 1, 3, 2, 4, 6, 5, 7, 8})
+	c.Check(len(visits), check.Equals, 8)
+	for _, n := range g.Nodes() {
+		c.Check(b.Visited(n), check.Equals, true)
+	}
+
+	b.Reset()
+	c.Check(b.Visited(g.Node(0)), check.Equals, false)
+	_, err = b.Search(g.Node(0), func(e Edge) bool { return e.Head().ID() != 8 }, func(n Node) bool { return n.ID() == 8 }, nil)
+	c.Check(err, check.Equals, notFound)
+}
+
+func (s *S) TestBreadthFirstPath(c *check.C) {
+	const rows, cols = 5, 7
+	g := grid(c, rows, cols, func(_, _ int) float64 { return 1 })
+	b := NewBreadthFirst()
+	for _, t := range []int{0, 1, cols, rows*cols - 1, 2*cols + 3} {
+		path, err := b.Path(g.Node(0), g.Node(t), all)
+		c.Assert(err, check.IsNil)
+		c.Check(len(path), check.Equals, t/cols+t%cols)
+		ids := pathNodes(g.Node(0), path)
+		c.Check(ids[len(ids)-1], check.Equals, t)
+		b.Reset()
+	}
+
+	g.AddID(rows * cols)
+	_, err := b.Path(g.Node(0), g.Node(rows*cols), all)
+	c.Check(err, check.Equals, notFound)
+}
+
+func (s *S) TestBreadthFirstPathRepeated(c *check.C) {
+	g := path(c, 5)
+	b := NewBreadthFirst()
+	for _, t := range []int{4, 2, 4} {
+		p, err := b.Path(g.Node(0), g.Node(t), all)
+		c.Assert(err, check.IsNil, check.Commentf("target %d", t))
+		c.Check(p, check.HasLen, t)
+	}
+	p, err := b.Path(g.Node(4), g.Node(0), all)
+	c.Assert(err, check.IsNil)
+	c.Check(p, check.HasLen, 4)
+	c.Check(b.Visited(g.Node(4)), check.Equals, true)
+}
+
+func (s *S) TestDepthFirst(c *check.C) {
+	g := path(c, 5)
+	g.ConnectByID(0, 4, 1, 0)
+	var order []int
+	d := NewDepthFirst()
+	n, err := d.Search(g.Node(0), all, func(n Node) bool { order = append(order, n.ID()); return false }, nil)
+	c.Check(n, check.IsNil)
+	c.Check(err, check.Equals, notFound)
+	c.Check(order, check.DeepEquals, []int{0, 4, 3, 2, 1})
+
+	d.Reset()
+	order = order[:0]
+	n, err = d.Search(g.Node(2), all, func(n Node) bool { order = append(order, n.ID()); return n.ID() == 0 }, nil)
+	c.Assert(err, check.IsNil)
+	c.Check(n, check.Equals, g.Node(0))
+	c.Check(order[0], check.Equals, 2)
+}
+
+func (s *S) TestBreadthFirstBitset(c *check.C) {
+	g := grid(c, 5, 7, func(_, _ int) float64 { return 1 })
+	g.AddID(200)
+	notCut := func(e Edge) bool { return e.Head().ID() != 17 && e.Tail().ID() != 17 }
+	for _, t := range []int{16, 34, 200} {
+		var want, got []int
+		bl := NewBreadthFirst()
+		bb := NewBreadthFirst()
+		bb.UseBitset(true)
+		nl, errl := bl.Search(g.Node(0), notCut, func(n Node) bool { want = append(want, n.ID()); return n.ID() == t }, nil)
+		nb, errb := bb.Search(g.Node(0), notCut, func(n Node) bool { got = append(got, n.ID()); return n.ID() == t }, nil)
+		c.Check(nb, check.Equals, nl)
+		c.Check(errb, check.Equals, errl)
+		c.Check(got, check.DeepEquals, want)
+		for id := -1; id < 300; id++ {
+			n := &node{id: id}
+			c.Check(bb.Visited(n), check.Equals, bl.Visited(n), check.Commentf("target %d node %d", t, id))
+		}
+
+		// Switching store keeps the visited set.
+		bb.UseBitset(false)
+		bl.UseBitset(true)
+		for id := -1; id < 300; id++ {
+			n := &node{id: id}
+			c.Check(bb.Visited(n), check.Equals, bl.Visited(n), check.Commentf("target %d node %d", t, id))
+		}
+
+		bl.Reset()
+		c.Check(bl.Visited(g.Node(0)), check.Equals, false)
+		p, err := bl.Path(g.Node(0), g.Node(34), all)
+		c.Assert(err, check.IsNil)
+		c.Check(p, check.HasLen, 10)
+	}
+}
+
+func (s *S) TestSizedSearchers(c *check.C) {
+	g := grid(c, 4, 4, func(_, _ int) float64 { return 1 })
+	for _, size := range []int{0, 4, 16, 100} {
+		var want, got []int
+		NewBreadthFirst().Search(g.Node(0), all, func(n Node) bool { want = append(want, n.ID()); return false }, nil)
+		b := NewBreadthFirstSize(size)
+		b.Search(g.Node(0), all, func(n Node) bool { got = append(got, n.ID()); return false }, nil)
+		c.Check(got, check.DeepEquals, want)
+		for _, n := range g.Nodes() {
+			c.Check(b.Visited(n), check.Equals, true)
+		}
+
+		want, got = want[:0], got[:0]
+		NewDepthFirst().Search(g.Node(0), all, func(n Node) bool { want = append(want, n.ID()); return false }, nil)
+		d := NewDepthFirstSize(size)
+		d.Search(g.Node(0), all, func(n Node) bool { got = append(got, n.ID()); return false }, nil)
+		c.Check(got, check.DeepEquals, want)
+	}
+}
+
+func (s *S) TestAStar(c *check.C) {
+	const rows, cols = 8, 9
+	g := grid(c, rows, cols, func(r, k int) float64 { return float64(1 + (r*7+k*3)%5) })
+	t := g.Node(rows*cols - 1)
+	manhattan := func(n Node) float64 {
+		id := n.ID()
+		dr, dc := rows-1-id/cols, cols-1-id%cols
+		return float64(dr + dc)
+	}
+
+	for _, h := range []Heuristic{nil, manhattan} {
+		as := NewAStar()
+		n, path, err := as.Search(g.Node(0), h, all, func(n Node) bool { return n == t })
+		c.Assert(err, check.IsNil)
+		c.Check(n, check.Equals, t)
+
+		_, want, err := g.ShortestPath(g.Node(0), t, all)
+		c.Assert(err, check.IsNil)
+		var cost float64
+		for _, e := range path {
+			cost += e.Weight()
+		}
+		c.Check(cost, check.Equals, want)
+		c.Check(pathNodes(g.Node(0), path)[len(path)], check.Equals, t.ID())
+	}
+}
+
+func benchmarkStar(n int) *Undirected {
+	pairs := make([][2]int, n-1)
+	for i := range pairs {
+		pairs[i] = [2]int{0, i + 1}
+	}
+	g := NewUndirected()
+	g.AddEdges(pairs, nil)
+	return g
+}
+
+func BenchmarkBreadthFirst(b *testing.B) {
+	g := benchmarkStar(1e5)
+	f := func(_ Node) bool { return false }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewBreadthFirst().Search(g.Node(0), all, f, nil)
+	}
+}
+
+func BenchmarkBreadthFirstSize(b *testing.B) {
+	g := benchmarkStar(1e5)
+	f := func(_ Node) bool { return false }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewBreadthFirstSize(g.Order()).Search(g.Node(0), all, f, nil)
+	}
+}
+
+func BenchmarkDepthFirst(b *testing.B) {
+	g := benchmarkStar(1e5)
+	f := func(_ Node) bool { return false }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewDepthFirst().Search(g.Node(0), all, f, nil)
+	}
+}
+
+func BenchmarkDepthFirstSize(b *testing.B) {
+	g := benchmarkStar(1e5)
+	f := func(_ Node) bool { return false }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		NewDepthFirstSize(g.Order()).Search(g.Node(0), all, f, nil)
+	}
+}
@@ -18,6 +18,7 @@ package graph
 import (
 	"errors"
 	"fmt"
+	"sync"
 )
 
 var (
@@ -27,10 +28,18 @@ var (
 	EdgeDoesNotExist = errors.New("graph: edge does not exist")
 )
 
+// MaxReadID is the largest node or edge ID accepted by ReadDOT, ReadEdgeList, ReadGML,
+// ReadMatrixMarket, UnmarshalJSON and GobDecode. Since a graph's storage grows with its largest ID,
+// the limit prevents a small input from forcing a large allocation. It may be raised to read graphs
+// with larger IDs.
+var MaxReadID = 1<<24 - 1
+
 // An Unidirected is a container for an undirected graph representation.
 type Undirected struct {
 	nodes, compNodes Nodes
 	edges, compEdges Edges
+
+	stats *statsCache
 }
 
 // NewUndirected creates a new empty Undirected graph.
@@ -73,6 +82,18 @@ func (g *Undirected) Nodes() Nodes {
 	return g.compNodes
 }
 
+// SortedNodes returns the nodes of the graph in ascending order of ID. Unlike Nodes, the order does not
+// depend on the history of additions and deletions.
+func (g *Undirected) SortedNodes() []Node {
+	nodes := make([]Node, 0, len(g.compNodes))
+	for _, n := range g.nodes {
+		if n != nil {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
 // Node returns the node with the specified ID.
 func (g *Undirected) Node(id int) Node {
 	if id >= len(g.nodes) {
@@ -86,6 +107,28 @@ func (g *Undirected) Edges() []Edge {
 	return g.compEdges
 }
 
+// SortedEdges returns the edges of the graph in ascending order of ID. Unlike Edges, the order does not
+// depend on the history of additions and deletions.
+func (g *Undirected) SortedEdges() []Edge {
+	edges := make([]Edge, 0, len(g.compEdges))
+	for _, e := range g.edges {
+		if e != nil {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// EachEdge calls fn for each edge of the graph in the order given by Edges, stopping if fn returns
+// false.
+func (g *Undirected) EachEdge(fn func(Edge) bool) {
+	for _, e := range g.compEdges {
+		if !fn(e) {
+			return
+		}
+	}
+}
+
 // Edge returns the edge with the specified ID.
 func (g *Undirected) Edge(id int) Edge {
 	if id >= len(g.edges) {
@@ -116,6 +159,7 @@ func (g *Undirected) Add(n Node) error {
 	}
 	n.setIndex(len(g.compNodes))
 	g.compNodes = append(g.compNodes, n)
+	g.invalidate()
 
 	return nil
 }
@@ -141,6 +185,7 @@ func (g *Undirected) AddID(id int) (Node, error) {
 	}
 	n.setIndex(len(g.compNodes))
 	g.compNodes = append(g.compNodes, n)
+	g.invalidate()
 
 	return n, nil
 }
@@ -170,9 +215,12 @@ func (g *Undirected) deleteNode(id int) {
 	for _, h := range n.Hops(f) {
 		h.Edge.disconnect(h.Node)
 		g.compEdges = g.compEdges.delFromGraph(h.Edge.index())
+		g.edges[h.Edge.ID()] = nil
+		h.Edge.setID(-1)
 	}
 	g.compNodes = g.compNodes.delFromGraph(n.index())
 	n.setID(-1)
+	g.invalidate()
 }
 
 // Has returns a boolean indicating whether the node n exists in the graph. If the ID of n is no in
@@ -222,6 +270,14 @@ func (g *Undirected) Merge(dst, src Node) error {
 	}
 
 	for _, e := range src.Edges() {
+		u, v := e.Nodes()
+		if u == src && v == src {
+			// Self-loops on src become self-loops on dst.
+			e.reconnect(src, dst)
+			e.reconnect(src, dst)
+			dst.add(e)
+			continue
+		}
 		e.reconnect(src, dst)
 		if e.Head() != e.Tail() {
 			dst.add(e)
@@ -234,24 +290,48 @@ func (g *Undirected) Merge(dst, src Node) error {
 	return nil
 }
 
+// ContractEdge deletes the edge e and merges the nodes it joined, keeping the tail of e and
+// transfering the edges of its head as for Merge. If merge is not nil it is called with the kept
+// and dropped nodes before the dropped node is removed, allowing data associated with the two
+// nodes to be combined. Contracting a self-loop deletes it without calling merge. If e does not
+// exist in the graph, EdgeDoesNotExist is returned.
+func (g *Undirected) ContractEdge(e Edge, merge func(keep, drop Node)) error {
+	i := e.index()
+	if i < 0 || i > len(g.compEdges)-1 || g.compEdges[i] != e {
+		return EdgeDoesNotExist
+	}
+
+	keep, drop := e.Tail(), e.Head()
+	if keep != drop && merge != nil {
+		merge(keep, drop)
+	}
+	g.DeleteEdge(e)
+	if keep == drop {
+		return nil
+	}
+
+	return g.Merge(keep, drop)
+}
+
 // Edge methods
 
-// newEdge makes a new edge joining u and v with weight w and edge flags f. The ID chosen for the
-// edge is NextEdgeID().
-func (g *Undirected) newEdge(u, v Node, w float64, f EdgeFlags) Edge {
-	e := newEdge(len(g.edges), len(g.compEdges), u, v, w, f)
+// newEdge makes a new edge joining u and v with weight w, edge flags f and label l. The ID chosen for
+// the edge is NextEdgeID().
+func (g *Undirected) newEdge(u, v Node, w float64, f EdgeFlags, l string) Edge {
+	e := newEdge(len(g.edges), len(g.compEdges), u, v, w, f, l)
 	g.edges = append(g.edges, e)
 	g.compEdges = append(g.compEdges, e)
+	g.invalidate()
 
 	return e
 }
 
-// newEdgeKeepID makes a new edge joining u and v with ID id, weight w and edge flags f.
-func (g *Undirected) newEdgeKeepID(id int, u, v Node, w float64, f EdgeFlags) Edge {
+// newEdgeKeepID makes a new edge joining u and v with ID id, weight w, edge flags f and label l.
+func (g *Undirected) newEdgeKeepID(id int, u, v Node, w float64, f EdgeFlags, l string) Edge {
 	if id < len(g.edges) && g.edges[id] != nil {
 		panic("graph: attempted to create a new edge with an existing ID")
 	}
-	e := newEdge(id, len(g.compEdges), u, v, w, f)
+	e := newEdge(id, len(g.compEdges), u, v, w, f, l)
 
 	if id == len(g.edges) {
 		g.edges = append(g.edges, e)
@@ -265,6 +345,7 @@ func (g *Undirected) newEdgeKeepID(id int, u, v Node, w float64, f EdgeFlags) Ed
 	}
 	e.setIndex(len(g.compEdges))
 	g.compEdges = append(g.compEdges, e)
+	g.invalidate()
 
 	return e
 }
@@ -292,6 +373,7 @@ func (g *Undirected) ConnectWith(u, v Node, with Edge) error {
 
 	g.edges = append(g.edges, e)
 	g.compEdges = append(g.compEdges, e)
+	g.invalidate()
 
 	u.add(e)
 	if v != u {
@@ -318,7 +400,7 @@ func (g *Undirected) Connect(u, v Node, w float64, f EdgeFlags) (Edge, error) {
 		return nil, err
 	}
 
-	e := g.newEdge(u, v, w, f)
+	e := g.newEdge(u, v, w, f, "")
 	u.add(e)
 	if v != u {
 		v.add(e)
@@ -344,7 +426,7 @@ func (g *Undirected) ConnectByID(uid, vid int, w float64, f EdgeFlags) (int, err
 		return -1, err
 	}
 
-	e := g.newEdge(g.nodes[uid], g.nodes[vid], w, f)
+	e := g.newEdge(g.nodes[uid], g.nodes[vid], w, f, "")
 	g.nodes[uid].add(e)
 	if vid != uid {
 		g.nodes[vid].add(e)
@@ -353,6 +435,38 @@ func (g *Undirected) ConnectByID(uid, vid int, w float64, f EdgeFlags) (int, err
 	return e.ID(), nil
 }
 
+// AddEdges creates an edge joining each pair of node IDs in pairs, adding nodes with those IDs to the
+// graph if they do not already exist. If weights is not nil it must hold a weight for each pair,
+// otherwise edges are given a weight of 1. Edges are created with no flags, in the order of pairs. If
+// the lengths of pairs and weights differ, or an ID is negative, an error is returned and the graph is
+// not altered.
+func (g *Undirected) AddEdges(pairs [][2]int, weights []float64) error {
+	if weights != nil && len(weights) != len(pairs) {
+		return fmt.Errorf("graph: %d weights given for %d edges", len(weights), len(pairs))
+	}
+	for _, p := range pairs {
+		if p[0] < 0 || p[1] < 0 {
+			return NodeIDOutOfRange
+		}
+	}
+
+	for i, p := range pairs {
+		u, _ := g.AddID(p[0])
+		v, _ := g.AddID(p[1])
+		w := 1.
+		if weights != nil {
+			w = weights[i]
+		}
+		e := g.newEdge(u, v, w, 0, "")
+		u.add(e)
+		if v != u {
+			v.add(e)
+		}
+	}
+
+	return nil
+}
+
 // Connected returns a boolean indicating whether the nodes u and v share an edge. An error is returned
 // if either of the nodes does not exist.
 func (g *Undirected) Connected(u, v Node) (bool, error) {
@@ -387,6 +501,13 @@ func (g *Undirected) Connected(u, v Node) (bool, error) {
 	return false, nil
 }
 
+// EdgesBetween is a convenience wrapper around ConnectingEdges for callers that do not need to
+// distinguish a missing node from a pair with no joining edges; in either case nil is returned.
+func (g *Undirected) EdgesBetween(a, b Node) []Edge {
+	c, _ := g.ConnectingEdges(a, b)
+	return c
+}
+
 // ConnectingEdges returns a slice of edges that are shared by nodes u and v. An error is returned
 // if either of the nodes does not exist.
 func (g *Undirected) ConnectingEdges(u, v Node) ([]Edge, error) {
@@ -433,37 +554,92 @@ func (g *Undirected) ConnectingEdges(u, v Node) ([]Edge, error) {
 // the graph.
 func (g *Undirected) DeleteEdge(e Edge) error {
 	i := e.index()
-	if i < 0 || i > len(g.compEdges)-1 {
+	if i < 0 || i > len(g.compEdges)-1 || g.compEdges[i] != e {
 		return EdgeDoesNotExist
 	}
 
-	e.disconnect(e.Head())
-	e.disconnect(e.Tail())
+	h, t := e.Head(), e.Tail()
+	e.disconnect(h)
+	if t != h {
+		e.disconnect(t)
+	}
 	g.compEdges = g.compEdges.delFromGraph(i)
 	g.edges[e.ID()] = nil
 	e.setID(-1)
+	g.invalidate()
 
 	return nil
 }
 
+// RemoveEdgesFunc deletes every edge in the graph for which pred returns true and returns the number
+// of edges deleted. pred is called for each edge, in order of edge ID, before any edge is deleted.
+func (g *Undirected) RemoveEdgesFunc(pred func(Edge) bool) int {
+	var del []Edge
+	for _, e := range g.edges {
+		if e != nil && pred(e) {
+			del = append(del, e)
+		}
+	}
+	for _, e := range del {
+		g.DeleteEdge(e)
+	}
+
+	return len(del)
+}
+
+// RemoveNodesFunc deletes every node in the graph for which pred returns true, along with the edges
+// incident on those nodes, and returns the number of nodes deleted. pred is called for each node, in
+// order of node ID, before any node is deleted.
+func (g *Undirected) RemoveNodesFunc(pred func(Node) bool) int {
+	var del []int
+	for _, n := range g.nodes {
+		if n != nil && pred(n) {
+			del = append(del, n.ID())
+		}
+	}
+	for _, id := range del {
+		g.deleteNode(id)
+	}
+
+	return len(del)
+}
+
+// ApplyCut sets the EdgeCut flag on each edge in cut that belongs to the graph, for example the edges
+// of a cut returned by FastRandMinCut. Edges with the flag set can be excluded from traversal with an
+// edge filter such as func(e Edge) bool { return e.Flags()&EdgeCut == 0 }.
+func (g *Undirected) ApplyCut(cut []Edge) {
+	for _, e := range cut {
+		if id := e.ID(); id >= 0 && id < len(g.edges) && g.edges[id] == e {
+			e.SetFlags(e.Flags() | EdgeCut)
+		}
+	}
+}
+
+// ClearCut clears the EdgeCut flag on every edge in the graph.
+func (g *Undirected) ClearCut() {
+	for _, e := range g.compEdges {
+		e.SetFlags(e.Flags() &^ EdgeCut)
+	}
+}
+
 // Structure methods
 
 // ConnectedComponents returns a slice of slices of nodes. Each top level slice is the set of nodes
 // composing a connected component of the graph. Connection is determined by traversal of edges that
-// satisfy the edge filter ef.
+// satisfy the edge filter ef. Nodes with no traversable edges form singleton components.
 func (g *Undirected) ConnectedComponents(ef EdgeFilter) []Nodes {
 	var cc []Nodes
-	df := NewDepthFirst()
+	bf := NewBreadthFirst()
 	c := []Node{}
 	f := func(n Node) bool {
 		c = append(c, n)
 		return false
 	}
 	for _, s := range g.compNodes {
-		if df.Visited(s) {
+		if bf.Visited(s) {
 			continue
 		}
-		df.Search(s, ef, f, nil)
+		bf.Search(s, ef, f, nil)
 		cc = append(cc, []Node{})
 		cc[len(cc)-1] = append(cc[len(cc)-1], c...)
 		c = c[:0]
@@ -472,6 +648,478 @@ func (g *Undirected) ConnectedComponents(ef EdgeFilter) []Nodes {
 	return cc
 }
 
+// LabelComponents returns the index of the connected component holding each node of the graph, keyed
+// by node ID. Two nodes have the same index if and only if they are joined by a path. Indices are
+// numbered from zero in the order the components are first encountered in Nodes. Components are found
+// by union-find over all edges of the graph.
+func (g *Undirected) LabelComponents() map[int]int {
+	ds := newDisjointSet(len(g.nodes))
+	for _, e := range g.compEdges {
+		ds.union(e.Head().ID(), e.Tail().ID())
+	}
+
+	labels := make(map[int]int, len(g.compNodes))
+	index := make(map[int]int)
+	for _, n := range g.compNodes {
+		r := ds.find(n.ID())
+		l, ok := index[r]
+		if !ok {
+			l = len(index)
+			index[r] = l
+		}
+		labels[n.ID()] = l
+	}
+
+	return labels
+}
+
+// ConnectedComponentsPar returns the connected components of the graph as ConnectedComponents does
+// with an edge filter accepting all edges, but divides the nodes of the graph between up to threads
+// goroutines, bounded by MaxProcs, which join the ends of the edges of their nodes in a shared
+// union-find forest. Components are ordered by their first node in Nodes, and the nodes of each
+// component are in the order given by Nodes.
+func (g *Undirected) ConnectedComponentsPar(threads int) [][]Node {
+	if len(g.compNodes) == 0 {
+		return nil
+	}
+	if threads > MaxProcs {
+		threads = MaxProcs
+	}
+	if threads > len(g.compNodes) {
+		threads = len(g.compNodes)
+	}
+	if threads < 1 {
+		threads = 1
+	}
+
+	ds := newConcurrentDisjointSet(len(g.nodes))
+	wg := &sync.WaitGroup{}
+	chunk := (len(g.compNodes) + threads - 1) / threads
+	for lo := 0; lo < len(g.compNodes); lo += chunk {
+		hi := lo + chunk
+		if hi > len(g.compNodes) {
+			hi = len(g.compNodes)
+		}
+		wg.Add(1)
+		go func(nodes Nodes) {
+			defer wg.Done()
+			for _, n := range nodes {
+				for _, e := range n.Edges() {
+					// Each edge is held by both its ends; join it from its tail only.
+					if e.Tail() == n {
+						ds.union(e.Head().ID(), e.Tail().ID())
+					}
+				}
+			}
+		}(g.compNodes[lo:hi])
+	}
+	wg.Wait()
+
+	var cc [][]Node
+	index := make(map[int]int)
+	for _, n := range g.compNodes {
+		r := ds.find(n.ID())
+		i, ok := index[r]
+		if !ok {
+			i = len(cc)
+			index[r] = i
+			cc = append(cc, nil)
+		}
+		cc[i] = append(cc[i], n)
+	}
+
+	return cc
+}
+
+// IsConnected returns a boolean indicating whether the graph is composed of a single connected
+// component. Connection is determined by traversal of edges that satisfy the edge filter ef. An
+// empty graph is considered to be connected.
+func (g *Undirected) IsConnected(ef EdgeFilter) bool {
+	if len(g.compNodes) == 0 {
+		return true
+	}
+	n := 0
+	f := func(_ Node) bool {
+		n++
+		return false
+	}
+	NewBreadthFirst().Search(g.compNodes[0], ef, f, nil)
+
+	return n == len(g.compNodes)
+}
+
+// Reachable returns a boolean indicating whether there is a path between the nodes a and b
+// traversing edges that satisfy the edge filter ef. Breadth-first searches are expanded from both a
+// and b, always from the smaller frontier, returning as soon as they meet; no path is built. A node
+// is reachable from itself. If either node does not exist in the graph, false is returned.
+func (g *Undirected) Reachable(a, b Node, ef EdgeFilter) bool {
+	if ok, _ := g.Has(a); !ok {
+		return false
+	}
+	if ok, _ := g.Has(b); !ok {
+		return false
+	}
+	if a == b {
+		return true
+	}
+
+	frontiers := [2][]Node{{a}, {b}}
+	visits := [2][]bool{mark(a, nil), mark(b, nil)}
+	for len(frontiers[0]) > 0 && len(frontiers[1]) > 0 {
+		i := 0
+		if len(frontiers[1]) < len(frontiers[0]) {
+			i = 1
+		}
+		var next []Node
+		for _, u := range frontiers[i] {
+			met := false
+			u.EachNeighbor(ef, func(v Node) bool {
+				if marked(v, visits[1-i]) {
+					met = true
+					return false
+				}
+				if !marked(v, visits[i]) {
+					visits[i] = mark(v, visits[i])
+					next = append(next, v)
+				}
+				return true
+			})
+			if met {
+				return true
+			}
+		}
+		frontiers[i] = next
+	}
+
+	return false
+}
+
+// IsCutEdge returns a boolean indicating whether removing the edge e would disconnect the nodes it
+// joins, that is whether e is a bridge. Reachability is checked with Reachable, traversing every edge
+// other than e, so an edge with a parallel edge is not a cut edge. Self-loops are never cut edges. If e
+// does not exist in the graph, false is returned.
+func (g *Undirected) IsCutEdge(e Edge) bool {
+	i := e.index()
+	if i < 0 || i > len(g.compEdges)-1 || g.compEdges[i] != e {
+		return false
+	}
+	u, v := e.Nodes()
+	return !g.Reachable(u, v, func(f Edge) bool { return f != e })
+}
+
+// DFSOrder performs a depth-first search of the graph from the node start, traversing edges that
+// satisfy the edge filter ef, and returns the discovery and finish times of each node reached, keyed
+// by node ID. Times are taken from a single counter that is incremented at each discovery and each
+// finish, so for any two nodes their [discovery, finish] intervals are either disjoint or nested. Nodes
+// are explored in the order their edges are held by each node.
+func (g *Undirected) DFSOrder(start Node, ef EdgeFilter) (discovery, finish map[int]int) {
+	discovery = make(map[int]int)
+	finish = make(map[int]int)
+	if ok, _ := g.Has(start); !ok {
+		return discovery, finish
+	}
+
+	type frame struct {
+		n    Node
+		hops []*Hop
+	}
+	var time int
+	discovery[start.ID()] = time
+	time++
+	stack := []frame{{n: start, hops: start.Hops(ef)}}
+	for len(stack) > 0 {
+		f := &stack[len(stack)-1]
+		if len(f.hops) == 0 {
+			finish[f.n.ID()] = time
+			time++
+			stack = stack[:len(stack)-1]
+			continue
+		}
+		v := f.hops[0].Node
+		f.hops = f.hops[1:]
+		if _, ok := discovery[v.ID()]; ok {
+			continue
+		}
+		discovery[v.ID()] = time
+		time++
+		stack = append(stack, frame{n: v, hops: v.Hops(ef)})
+	}
+
+	return discovery, finish
+}
+
+// Neighborhood returns the nodes that can be reached from the node n by traversing at most hops
+// edges that satisfy the edge filter ef, including n itself. Nodes are returned in breadth-first
+// order. If n does not exist in the graph or hops is negative, nil is returned.
+func (g *Undirected) Neighborhood(n Node, hops int, ef EdgeFilter) []Node {
+	if ok, _ := g.Has(n); !ok || hops < 0 {
+		return nil
+	}
+
+	var seen []bool
+	seen = mark(n, seen)
+	hood := []Node{n}
+	frontier := hood
+	for ; hops > 0 && len(frontier) > 0; hops-- {
+		next := len(hood)
+		for _, u := range frontier {
+			u.EachNeighbor(ef, func(v Node) bool {
+				if !marked(v, seen) {
+					seen = mark(v, seen)
+					hood = append(hood, v)
+				}
+				return true
+			})
+		}
+		frontier = hood[next:]
+	}
+
+	return hood
+}
+
+// Clone returns an independent copy of the graph. Node and edge IDs, edge weights and edge flags
+// are preserved, as are the values of NextNodeID and NextEdgeID.
+func (g *Undirected) Clone() *Undirected {
+	c := &Undirected{
+		nodes:     make(Nodes, len(g.nodes)),
+		compNodes: make(Nodes, 0, len(g.compNodes)),
+		edges:     make(Edges, len(g.edges)),
+		compEdges: make(Edges, 0, len(g.compEdges)),
+	}
+	for _, n := range g.nodes {
+		if n != nil {
+			c.Add(newNode(n.ID()))
+		}
+	}
+	for _, e := range g.edges {
+		if e == nil {
+			continue
+		}
+		u, v := c.nodes[e.Tail().ID()], c.nodes[e.Head().ID()]
+		ne := c.newEdgeKeepID(e.ID(), u, v, e.Weight(), e.Flags(), e.Label())
+		u.add(ne)
+		if v != u {
+			v.add(ne)
+		}
+	}
+	c.EnableStatsCache(g.stats != nil)
+
+	return c
+}
+
+// Equal returns a boolean indicating whether g and h hold the same set of node IDs and the same
+// multiset of edges, where edges are matched by the IDs of the nodes they join, their weights and
+// their flags. Edge IDs and labels are not compared. Equal is not a test of isomorphism; graphs that
+// are isomorphic under a different assignment of node IDs are not equal.
+func (g *Undirected) Equal(h *Undirected) bool {
+	if len(g.compNodes) != len(h.compNodes) || len(g.compEdges) != len(h.compEdges) {
+		return false
+	}
+	for _, n := range g.compNodes {
+		if ok, _ := h.HasNodeID(n.ID()); !ok {
+			return false
+		}
+	}
+
+	type edgeKey struct {
+		u, v int
+		w    float64
+		f    EdgeFlags
+	}
+	key := func(e Edge) edgeKey {
+		u, v := e.Nodes()
+		uid, vid := u.ID(), v.ID()
+		if uid > vid {
+			uid, vid = vid, uid
+		}
+		return edgeKey{u: uid, v: vid, w: e.Weight(), f: e.Flags()}
+	}
+	count := make(map[edgeKey]int, len(g.compEdges))
+	for _, e := range g.compEdges {
+		count[key(e)]++
+	}
+	for _, e := range h.compEdges {
+		k := key(e)
+		if count[k] == 0 {
+			return false
+		}
+		count[k]--
+	}
+
+	return true
+}
+
+// Complement returns the complement of the graph: a graph with the same node IDs in which each pair
+// of distinct nodes is joined by an edge of weight 1 if and only if the pair is not joined by any edge
+// in g. Parallel edges in g are treated as a single adjacency, and self-loops are not included.
+func (g *Undirected) Complement() *Undirected {
+	c := NewUndirected()
+	for _, n := range g.nodes {
+		if n != nil {
+			c.AddID(n.ID())
+		}
+	}
+
+	adj := make([]bool, len(g.nodes))
+	for _, u := range g.nodes {
+		if u == nil {
+			continue
+		}
+		for _, e := range u.Edges() {
+			adj[adjacent(e, u).ID()] = true
+		}
+		for _, v := range g.nodes[u.ID()+1:] {
+			if v != nil && !adj[v.ID()] {
+				c.ConnectByID(u.ID(), v.ID(), 1, 0)
+			}
+		}
+		for _, e := range u.Edges() {
+			adj[adjacent(e, u).ID()] = false
+		}
+	}
+
+	return c
+}
+
+// Simplify returns a copy of the graph in which each set of parallel edges joining the same pair of
+// nodes is replaced by a single edge. The edges of each set are combined in order of edge ID by
+// successive calls to keep, which is passed the current result and the next edge and returns either
+// of them or a new edge, for example one created with NewEdge holding the sum of their weights. The
+// weight, flags and label of the final result are given to the replacement edge, which takes the ID
+// of the lowest ID edge of the set. If keep is nil, the lowest ID edge is kept. Self-loops are
+// simplified in the same way if keepLoops is true, and are removed otherwise. Node IDs are preserved.
+func (g *Undirected) Simplify(keep func(a, b Edge) Edge, keepLoops bool) *Undirected {
+	s := NewUndirected()
+	for _, n := range g.nodes {
+		if n != nil {
+			s.AddID(n.ID())
+		}
+	}
+
+	type pair struct{ u, v int }
+	var (
+		order  []pair
+		groups = make(map[pair][]Edge)
+	)
+	for _, e := range g.edges {
+		if e == nil {
+			continue
+		}
+		u, v := e.Tail().ID(), e.Head().ID()
+		if u == v && !keepLoops {
+			continue
+		}
+		if u > v {
+			u, v = v, u
+		}
+		p := pair{u, v}
+		if _, ok := groups[p]; !ok {
+			order = append(order, p)
+		}
+		groups[p] = append(groups[p], e)
+	}
+
+	for _, p := range order {
+		es := groups[p]
+		r := es[0]
+		if keep != nil {
+			for _, e := range es[1:] {
+				r = keep(r, e)
+			}
+		}
+		u, v := s.nodes[es[0].Tail().ID()], s.nodes[es[0].Head().ID()]
+		ne := s.newEdgeKeepID(es[0].ID(), u, v, r.Weight(), r.Flags(), r.Label())
+		u.add(ne)
+		if v != u {
+			v.add(ne)
+		}
+	}
+
+	return s
+}
+
+// Subgraph returns the subgraph of g induced by nodes, holding those nodes and the edges of g that
+// join them. Unlike Nodes.BuildUndirected, edges leading to nodes outside the set are not included.
+// If compact is set to true, edge IDs are chosen to minimize space consumption, but breaking edge ID
+// consistency between the new graph and the original. If any of the nodes is not in g, an error is
+// returned.
+func (g *Undirected) Subgraph(nodes []Node, compact bool) (*Undirected, error) {
+	in := make(map[Node]struct{}, len(nodes))
+	sg := NewUndirected()
+	for _, n := range nodes {
+		if ok, err := g.Has(n); !ok || g.nodes[n.ID()] != n {
+			if err == nil {
+				err = NodeDoesNotExist
+			}
+			return nil, err
+		}
+		in[n] = struct{}{}
+		sg.AddID(n.ID())
+	}
+
+	seen := make(map[Edge]struct{})
+	for _, n := range nodes {
+		for _, e := range n.Edges() {
+			if _, ok := seen[e]; ok {
+				continue
+			}
+			seen[e] = struct{}{}
+			u, v := e.Nodes()
+			if _, ok := in[u]; !ok {
+				continue
+			}
+			if _, ok := in[v]; !ok {
+				continue
+			}
+			uid, vid := u.ID(), v.ID()
+			var ne Edge
+			if compact {
+				ne = sg.newEdge(sg.nodes[uid], sg.nodes[vid], e.Weight(), e.Flags(), e.Label())
+			} else {
+				ne = sg.newEdgeKeepID(e.ID(), sg.nodes[uid], sg.nodes[vid], e.Weight(), e.Flags(), e.Label())
+			}
+			sg.nodes[uid].add(ne)
+			if vid != uid {
+				sg.nodes[vid].add(ne)
+			}
+		}
+	}
+
+	return sg, nil
+}
+
+// AdjacencyMatrix returns the adjacency matrix of the graph. Rows and columns of m are ordered by
+// node ID, with ids[i] holding the ID of the node corresponding to row and column i. m[i][j] is the
+// sum of the weights of the edges joining nodes ids[i] and ids[j], or the number of such edges if
+// unweighted is true. Self-loops contribute their weight, or a count of one, to the diagonal.
+func (g *Undirected) AdjacencyMatrix(unweighted bool) (m [][]float64, ids []int) {
+	idx := make([]int, len(g.nodes))
+	for _, n := range g.nodes {
+		if n != nil {
+			idx[n.ID()] = len(ids)
+			ids = append(ids, n.ID())
+		}
+	}
+
+	m = make([][]float64, len(ids))
+	for i := range m {
+		m[i] = make([]float64, len(ids))
+	}
+	for _, e := range g.compEdges {
+		w := 1.
+		if !unweighted {
+			w = e.Weight()
+		}
+		i, j := idx[e.Head().ID()], idx[e.Tail().ID()]
+		m[i][j] += w
+		if i != j {
+			m[j][i] += w
+		}
+	}
+
+	return m, ids
+}
+
 func (g *Undirected) String() string {
 	return fmt.Sprintf("G:|V|=%d |E|=%d", g.Order(), g.Size())
 }
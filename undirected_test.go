@@ -18,6 +18,9 @@ package graph
 import (
 	"fmt"
 	check "launchpad.net/gocheck"
+	"math/rand"
+	"sort"
+	"testing"
 )
 
 // Tests
@@ -62,6 +65,39 @@ func (s *S) TestUndirected(c *check.C) {
 	c.Check(g.Size(), check.Equals, len(uv))
 }
 
+func (s *S) TestUndirectedSortedNodes(c *check.C) {
+	g := undirected(c, []e{{5, 3}, {3, 8}, {0, 8}, {2, 5}})
+	g.AddID(12)
+	c.Assert(g.DeleteByID(3), check.IsNil)
+	g.AddID(1)
+	c.Assert(g.DeleteByID(0), check.IsNil)
+	g.AddID(3)
+
+	var ids []int
+	for _, n := range g.SortedNodes() {
+		ids = append(ids, n.ID())
+	}
+	c.Check(ids, check.DeepEquals, []int{1, 2, 3, 5, 8, 12})
+	c.Check(g.SortedNodes(), check.HasLen, g.Order())
+	c.Check(NewUndirected().SortedNodes(), check.HasLen, 0)
+}
+
+func (s *S) TestUndirectedSortedEdges(c *check.C) {
+	g := undirected(c, []e{{0, 1}, {1, 2}, {0, 1}, {2, 3}, {3, 0}, {1, 1}})
+	c.Assert(g.DeleteEdge(g.Edge(1)), check.IsNil)
+	c.Assert(g.DeleteEdge(g.Edge(4)), check.IsNil)
+	g.ConnectByID(0, 1, 1, 0)
+	c.Assert(g.DeleteByID(3), check.IsNil)
+
+	var ids []int
+	for _, e := range g.SortedEdges() {
+		ids = append(ids, e.ID())
+	}
+	c.Check(ids, check.DeepEquals, []int{0, 2, 5, 6})
+	c.Check(g.SortedEdges(), check.HasLen, g.Size())
+	c.Check(NewUndirected().SortedEdges(), check.HasLen, 0)
+}
+
 func (s *S) TestUndirectedMerge(c *check.C) {
 	g := undirected(c, uv)
 	order := g.Order()
@@ -92,6 +128,173 @@ func (s *S) TestUndirectedMerge(c *check.C) {
 	c.Check(len(conn), check.Equals, 2)
 }
 
+func (s *S) TestUndirectedMergeSelfLoop(c *check.C) {
+	g := undirected(c, []e{{0, 1}, {1, 1}, {1, 2}})
+	c.Assert(g.Merge(g.Node(0), g.Node(1)), check.IsNil)
+	c.Check(g.Order(), check.Equals, 2)
+	c.Check(g.Size(), check.Equals, 3)
+	for _, e := range g.Edges() {
+		u, v := e.Nodes()
+		c.Check(u == g.Node(1) || v == g.Node(1), check.Equals, false)
+	}
+	conn, err := g.ConnectingEdges(g.Node(0), g.Node(0))
+	c.Assert(err, check.IsNil)
+	c.Check(conn, check.HasLen, 2)
+	c.Check(g.Node(0).Degree(), check.Equals, 5)
+	c.Check(g.Node(0).Edges(), check.HasLen, 3)
+}
+
+func (s *S) TestUndirectedDeleteSelfLoop(c *check.C) {
+	g := undirected(c, []e{{0, 1}, {1, 1}})
+	conn, err := g.ConnectingEdges(g.Node(1), g.Node(1))
+	c.Assert(err, check.IsNil)
+	c.Assert(conn, check.HasLen, 1)
+	c.Assert(g.DeleteEdge(conn[0]), check.IsNil)
+	c.Check(g.Size(), check.Equals, 1)
+	c.Check(g.Node(1).Edges(), check.HasLen, 1)
+	c.Check(g.Node(1).Degree(), check.Equals, 1)
+
+	other := undirected(c, []e{{0, 1}})
+	c.Check(g.DeleteEdge(other.Edge(0)), check.Equals, EdgeDoesNotExist)
+	c.Check(g.Size(), check.Equals, 1)
+}
+
+func (s *S) TestNodeSimpleDegree(c *check.C) {
+	g := undirected(c, []e{{0, 1}, {0, 2}, {0, 0}})
+	n := g.Node(0)
+	c.Check(n.Degree(), check.Equals, 4)
+	c.Check(n.SimpleDegree(all), check.Equals, 3)
+	c.Check(n.SimpleDegree(func(e Edge) bool { return e.Head() != e.Tail() }), check.Equals, 2)
+	c.Check(g.Node(1).SimpleDegree(all), check.Equals, g.Node(1).Degree())
+
+	d := directed(c, []e{{0, 1}, {2, 0}, {0, 0}})
+	c.Check(d.Node(0).Degree(), check.Equals, 4)
+	c.Check(d.Node(0).SimpleDegree(all), check.Equals, 3)
+}
+
+func (s *S) TestNodeStrength(c *check.C) {
+	g := weightedUndirected(c, []we{{0, 1, 1}, {0, 2, 2}, {3, 0, 3}})
+	c.Check(g.Node(0).Strength(all), check.Equals, 6.)
+	c.Check(g.Node(2).Strength(all), check.Equals, 2.)
+
+	g.ConnectByID(0, 0, 0.5, 0)
+	c.Check(g.Node(0).Strength(all), check.Equals, 7.)
+	ce, _ := g.ConnectingEdges(g.Node(0), g.Node(3))
+	g.ApplyCut(ce)
+	c.Check(g.Node(0).Strength(func(e Edge) bool { return e.Flags()&EdgeCut == 0 }), check.Equals, 4.)
+}
+
+func (s *S) TestUndirectedContractEdge(c *check.C) {
+	g := undirected(c, []e{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {3, 3}})
+	mass := map[int]float64{0: 1, 1: 2, 2: 4, 3: 8}
+	sum := func(keep, drop Node) {
+		c.Check(g.Node(drop.ID()), check.Equals, drop)
+		mass[keep.ID()] += mass[drop.ID()]
+		delete(mass, drop.ID())
+	}
+
+	ce, _ := g.ConnectingEdges(g.Node(0), g.Node(1))
+	c.Assert(ce, check.HasLen, 1)
+	keep := ce[0].Tail().ID()
+	c.Assert(g.ContractEdge(ce[0], sum), check.IsNil)
+	c.Check(g.Order(), check.Equals, 3)
+	c.Check(g.Size(), check.Equals, 4)
+	c.Check(mass[keep], check.Equals, 3.)
+	c.Check(g.Node(keep).Degree(), check.Equals, 2)
+
+	ce, _ = g.ConnectingEdges(g.Node(2), g.Node(3))
+	c.Assert(ce, check.HasLen, 1)
+	c.Assert(g.ContractEdge(ce[0], sum), check.IsNil)
+	c.Check(g.Order(), check.Equals, 2)
+	c.Check(g.Size(), check.Equals, 3)
+	c.Check(len(mass), check.Equals, 2)
+	total := 0.
+	for _, m := range mass {
+		total += m
+	}
+	c.Check(total, check.Equals, 15.)
+
+	for _, e := range g.Edges() {
+		if u, v := e.Nodes(); u == v {
+			c.Assert(g.ContractEdge(e, sum), check.IsNil)
+			break
+		}
+	}
+	c.Check(g.Order(), check.Equals, 2)
+	c.Check(g.Size(), check.Equals, 2)
+	c.Check(len(mass), check.Equals, 2)
+
+	c.Check(g.ContractEdge(ce[0], nil), check.Equals, EdgeDoesNotExist)
+}
+
+func (s *S) TestUndirectedEqual(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	h := weightedUndirected(c, wuv)
+	c.Check(g.Equal(h), check.Equals, true)
+	c.Check(g.Equal(g.Clone()), check.Equals, true)
+
+	h.Edge(3).SetWeight(h.Edge(3).Weight() + 1)
+	c.Check(g.Equal(h), check.Equals, false)
+	c.Check(h.Equal(g), check.Equals, false)
+
+	// Edges are matched regardless of direction and order of addition.
+	r := NewUndirected()
+	for i := len(wuv) - 1; i >= 0; i-- {
+		u, _ := r.AddID(wuv[i].v)
+		v, _ := r.AddID(wuv[i].u)
+		r.Connect(u, v, wuv[i].w, 0)
+	}
+	c.Check(g.Equal(r), check.Equals, true)
+
+	r.Edge(0).SetFlags(EdgeCut)
+	c.Check(g.Equal(r), check.Equals, false)
+
+	h = g.Clone()
+	h.AddID(100)
+	c.Check(g.Equal(h), check.Equals, false)
+}
+
+func (s *S) TestUndirectedAddEdges(c *check.C) {
+	g := NewUndirected()
+	c.Assert(g.AddEdges([][2]int{{0, 1}, {1, 2}, {2, 0}}, []float64{1, 2, 3}), check.IsNil)
+	c.Check(g.Order(), check.Equals, 3)
+	c.Check(g.Size(), check.Equals, 3)
+	c.Check(g.Girth(), check.Equals, 3)
+	for i, e := range g.Edges() {
+		c.Check(e.ID(), check.Equals, i)
+		c.Check(e.Weight(), check.Equals, float64(i+1))
+	}
+
+	c.Assert(g.AddEdges([][2]int{{2, 5}, {5, 5}}, nil), check.IsNil)
+	c.Check(g.Order(), check.Equals, 4)
+	c.Check(g.Size(), check.Equals, 5)
+	c.Check(g.Node(5).Degree(), check.Equals, 3)
+	c.Check(g.Edge(4).Weight(), check.Equals, 1.)
+
+	c.Check(g.AddEdges([][2]int{{0, 1}, {1, 2}}, []float64{1}), check.ErrorMatches, "graph: 1 weights given for 2 edges")
+	c.Check(g.AddEdges([][2]int{{0, 7}, {-1, 2}}, nil), check.Equals, NodeIDOutOfRange)
+	c.Check(g.Order(), check.Equals, 4)
+	c.Check(g.Size(), check.Equals, 5)
+}
+
+func (s *S) TestUndirectedEdgesBetween(c *check.C) {
+	g := undirected(c, []e{{0, 1}, {1, 0}, {0, 2}, {0, 1}, {1, 1}, {2, 3}, {1, 1}})
+	n := g.Node
+	between := func(a, b Node) []int {
+		var ids []int
+		for _, e := range g.EdgesBetween(a, b) {
+			ids = append(ids, e.ID())
+		}
+		return ids
+	}
+	c.Check(between(n(0), n(1)), check.DeepEquals, []int{0, 1, 3})
+	c.Check(between(n(1), n(0)), check.DeepEquals, []int{0, 1, 3})
+	c.Check(between(n(1), n(1)), check.DeepEquals, []int{4, 6})
+	c.Check(between(n(0), n(0)), check.HasLen, 0)
+	c.Check(between(n(1), n(3)), check.HasLen, 0)
+	c.Check(g.EdgesBetween(n(0), g.NewNode()), check.IsNil)
+}
+
 func (s *S) TestUndirectedConnected(c *check.C) {
 	g := undirected(c, uv)
 	n := g.Nodes()
@@ -108,6 +311,20 @@ func (s *S) TestUndirectedConnected(c *check.C) {
 	c.Check(conns, check.Equals, 2*g.Size()+g.Order())
 }
 
+func (s *S) TestUndirectedDeleteNodeEdges(c *check.C) {
+	g := undirected(c, uv)
+	size := g.Size()
+	var ids []int
+	for _, e := range g.Node(7).Edges() {
+		ids = append(ids, e.ID())
+	}
+	c.Assert(g.DeleteByID(7), check.IsNil)
+	c.Check(g.Size(), check.Equals, size-len(ids))
+	for _, id := range ids {
+		c.Check(g.Edge(id), check.IsNil, check.Commentf("edge %d", id))
+	}
+}
+
 func (s *S) TestUndirectedConnectedComponent(c *check.C) {
 	g := undirected(c, uv)
 	f := func(_ Edge) bool { return true }
@@ -137,6 +354,80 @@ func (s *S) TestUndirectedConnectedComponent(c *check.C) {
 	}
 }
 
+func (s *S) TestUndirectedLabelComponents(c *check.C) {
+	g := undirected(c, uv)
+	g.DeleteByID(deleteNode)
+	g.AddID(20)
+	labels := g.LabelComponents()
+	c.Check(labels, check.HasLen, g.Order())
+	for _, u := range g.Nodes() {
+		for _, v := range g.Nodes() {
+			_, err := NewBreadthFirst().Path(u, v, all)
+			c.Check(labels[u.ID()] == labels[v.ID()], check.Equals, err == nil, check.Commentf("%d %d", u.ID(), v.ID()))
+		}
+	}
+	seen := make(map[int]bool)
+	for _, l := range labels {
+		seen[l] = true
+	}
+	c.Check(seen, check.DeepEquals, map[int]bool{0: true, 1: true, 2: true})
+}
+
+func (s *S) TestUndirectedRemoveFunc(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	n := g.RemoveEdgesFunc(func(e Edge) bool { return e.Weight() > 10 })
+	c.Check(n, check.Equals, 3)
+	c.Check(g.Size(), check.Equals, len(wuv)-3)
+	for _, e := range g.Edges() {
+		c.Check(e.Weight() <= 10, check.Equals, true)
+	}
+	for _, n := range g.Nodes() {
+		for _, e := range n.Edges() {
+			c.Check(e.Weight() <= 10, check.Equals, true)
+		}
+	}
+	c.Check(g.RemoveEdgesFunc(func(e Edge) bool { return e.Weight() > 10 }), check.Equals, 0)
+
+	size := g.Size() - g.Node(2).Degree() - g.Node(4).Degree()
+	n = g.RemoveNodesFunc(func(n Node) bool { return n.ID()%2 == 0 && n.ID() != 0 })
+	c.Check(n, check.Equals, 2)
+	c.Check(g.Order(), check.Equals, 4)
+	c.Check(g.Size(), check.Equals, size)
+	for _, e := range g.Edges() {
+		for _, id := range []int{e.Head().ID(), e.Tail().ID()} {
+			c.Check(id != 2 && id != 4, check.Equals, true)
+		}
+	}
+}
+
+func (s *S) TestUndirectedConnectedComponentsPar(c *check.C) {
+	sets := func(cc [][]Node) [][]int {
+		var ids [][]int
+		for _, comp := range cc {
+			var id []int
+			for _, n := range comp {
+				id = append(id, n.ID())
+			}
+			sort.Ints(id)
+			ids = append(ids, id)
+		}
+		return ids
+	}
+	for seed := int64(0); seed < 10; seed++ {
+		g := GNP(200, 0.006, rand.NewSource(seed))
+		var serial [][]Node
+		for _, comp := range g.ConnectedComponents(all) {
+			serial = append(serial, comp)
+		}
+		want := sets(serial)
+		c.Check(len(want) > 1, check.Equals, true)
+		for _, threads := range []int{1, 2, 3, 8, 1000} {
+			c.Check(sets(g.ConnectedComponentsPar(threads)), check.DeepEquals, want)
+		}
+	}
+	c.Check(NewUndirected().ConnectedComponentsPar(4), check.HasLen, 0)
+}
+
 func (s *S) TestUndirectedBuild(c *check.C) {
 	g := undirected(c, uv)
 	g0, err := g.Nodes().BuildUndirected(false)
@@ -171,3 +462,350 @@ func (s *S) TestUndirectedRepresentation(c *check.C) {
 		c.Check(n.String(), check.Equals, reps[n.ID()])
 	}
 }
+
+func (s *S) TestUndirectedReachable(c *check.C) {
+	g := undirected(c, []e{{0, 1}, {1, 2}, {2, 3}, {3, 4}, {5, 6}, {6, 7}, {7, 5}})
+	g.AddID(8)
+	cc := g.ConnectedComponents(all)
+	c.Assert(cc, check.HasLen, 3)
+	label := make(map[int]int)
+	for i, comp := range cc {
+		for _, n := range comp {
+			label[n.ID()] = i
+		}
+	}
+	for _, u := range g.Nodes() {
+		for _, v := range g.Nodes() {
+			c.Check(g.Reachable(u, v, all), check.Equals, label[u.ID()] == label[v.ID()], check.Commentf("%d-%d", u.ID(), v.ID()))
+		}
+	}
+
+	notCut := func(e Edge) bool { return e.Flags()&EdgeCut == 0 }
+	ce, _ := g.ConnectingEdges(g.Node(2), g.Node(3))
+	g.ApplyCut(ce)
+	c.Check(g.Reachable(g.Node(0), g.Node(4), notCut), check.Equals, false)
+	c.Check(g.Reachable(g.Node(0), g.Node(2), notCut), check.Equals, true)
+	c.Check(g.Reachable(g.Node(0), g.Node(4), all), check.Equals, true)
+
+	c.Check(g.Reachable(g.Node(8), g.Node(8), all), check.Equals, true)
+	c.Check(g.Reachable(g.Node(0), g.NewNode(), all), check.Equals, false)
+}
+
+func (s *S) TestUndirectedIsCutEdge(c *check.C) {
+	// A triangle joined to a pair with parallel edges by the bridge 2-3.
+	g := undirected(c, []e{{0, 1}, {1, 2}, {2, 0}, {2, 3}, {3, 4}, {4, 3}, {4, 4}})
+	for id, want := range []bool{false, false, false, true, false, false, false} {
+		c.Check(g.IsCutEdge(g.Edge(id)), check.Equals, want, check.Commentf("edge %v", g.Edge(id)))
+	}
+
+	e := g.Edge(5)
+	c.Assert(g.DeleteEdge(e), check.IsNil)
+	c.Check(g.IsCutEdge(g.Edge(4)), check.Equals, true)
+	c.Check(g.IsCutEdge(e), check.Equals, false)
+}
+
+func (s *S) TestUndirectedNeighborhood(c *check.C) {
+	const n = 10
+	g := path(c, n)
+	for hops := 0; hops < n+2; hops++ {
+		hood := g.Neighborhood(g.Node(0), hops, all)
+		want := hops + 1
+		if want > n {
+			want = n
+		}
+		c.Check(hood, check.HasLen, want, check.Commentf("hops=%d", hops))
+		for i, v := range hood {
+			c.Check(v.ID(), check.Equals, i)
+		}
+	}
+
+	hood := g.Neighborhood(g.Node(5), 2, all)
+	var ids []int
+	for _, v := range hood {
+		ids = append(ids, v.ID())
+	}
+	c.Check(ids, check.DeepEquals, []int{5, 4, 6, 3, 7})
+
+	c.Check(g.Neighborhood(g.Node(5), 3, func(e Edge) bool { return e.Head().ID() != 7 && e.Tail().ID() != 7 }), check.HasLen, 5)
+	c.Check(g.Neighborhood(g.Node(5), -1, all), check.IsNil)
+	c.Check(g.Neighborhood(g.NewNode(), 1, all), check.IsNil)
+}
+
+func (s *S) TestUndirectedIsConnected(c *check.C) {
+	g := undirected(c, uv)
+	f := func(_ Edge) bool { return true }
+	c.Check(g.IsConnected(f), check.Equals, true)
+	c.Check(g.IsConnected(func(e Edge) bool { return e.Head().ID() != 9 && e.Tail().ID() != 9 }), check.Equals, false)
+	g.AddID(10)
+	c.Check(g.IsConnected(f), check.Equals, false)
+	cc := g.ConnectedComponents(f)
+	c.Check(len(cc), check.Equals, 2)
+	c.Check(cc[1], check.DeepEquals, Nodes{g.Node(10)})
+	c.Check(NewUndirected().IsConnected(f), check.Equals, true)
+}
+
+func (s *S) TestUndirectedAdjacencyMatrix(c *check.C) {
+	g := weightedUndirected(c, []we{{0, 2, 1}, {2, 5, 2}, {5, 0, 3}, {2, 0, 0.5}})
+	m, ids := g.AdjacencyMatrix(false)
+	c.Check(ids, check.DeepEquals, []int{0, 2, 5})
+	c.Check(m, check.DeepEquals, [][]float64{
+		{0, 1.5, 3},
+		{1.5, 0, 2},
+		{3, 2, 0},
+	})
+
+	g.ConnectByID(5, 5, 4, 0)
+	m, _ = g.AdjacencyMatrix(true)
+	c.Check(m, check.DeepEquals, [][]float64{
+		{0, 2, 1},
+		{2, 0, 1},
+		{1, 1, 1},
+	})
+	m, _ = g.AdjacencyMatrix(false)
+	c.Check(m[2][2], check.Equals, 4.)
+}
+
+func (s *S) TestUndirectedSubgraph(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	g.ConnectByID(2, 2, 1, 0)
+	sg, err := g.Subgraph([]Node{g.Node(0), g.Node(2), g.Node(5)}, false)
+	c.Assert(err, check.IsNil)
+	c.Check(sg.Order(), check.Equals, 3)
+	c.Check(sg.Size(), check.Equals, 4)
+	for _, id := range []int{1, 2, 6, 9} {
+		e := sg.Edge(id)
+		c.Assert(e, check.NotNil)
+		c.Check(e.Weight(), check.Equals, g.Edge(id).Weight())
+	}
+	c.Check(sg.Edge(0), check.IsNil)
+	c.Check(sg.Node(2).Degree(), check.Equals, 4)
+
+	sg, err = g.Subgraph([]Node{g.Node(3), g.Node(4)}, true)
+	c.Assert(err, check.IsNil)
+	c.Check(sg.Size(), check.Equals, 1)
+	c.Check(sg.Edge(0).Weight(), check.Equals, 6.)
+
+	_, err = g.Subgraph([]Node{newNode(7)}, false)
+	c.Check(err, check.NotNil)
+}
+
+func (s *S) TestUndirectedClone(c *check.C) {
+	g := weightedUndirected(c, wuv)
+	g.Edge(2).SetFlags(EdgeCut)
+	g.DeleteByID(1)
+	cl := g.Clone()
+	c.Check(cl.Order(), check.Equals, g.Order())
+	c.Check(cl.Size(), check.Equals, g.Size())
+	c.Check(cl.NextNodeID(), check.Equals, g.NextNodeID())
+	c.Check(cl.NextEdgeID(), check.Equals, g.NextEdgeID())
+	c.Check(cl.Edge(2).Flags(), check.Equals, EdgeCut)
+	for _, e := range g.Edges() {
+		ce := cl.Edge(e.ID())
+		c.Check(ce.Weight(), check.Equals, e.Weight())
+		c.Check(ce.Tail().ID(), check.Equals, e.Tail().ID())
+		c.Check(ce.Head().ID(), check.Equals, e.Head().ID())
+		ce.SetWeight(-1)
+		c.Check(e.Weight() >= 0, check.Equals, true)
+	}
+
+	cl.DeleteByID(0)
+	cl.ConnectByID(3, 5, 1, 0)
+	c.Check(g.Order(), check.Equals, 5)
+	c.Check(g.Size(), check.Equals, 6)
+	c.Check(g.Node(0).Degree(), check.Equals, 2)
+}
+
+func (s *S) TestUndirectedEdgeLabel(c *check.C) {
+	g := path(c, 2)
+	e0, err := g.Connect(g.Node(0), g.Node(1), 1, 0)
+	c.Assert(err, check.IsNil)
+	e0.SetLabel("likes")
+	e1, err := g.Connect(g.Node(0), g.Node(1), 1, 0)
+	c.Assert(err, check.IsNil)
+	e1.SetLabel("follows")
+
+	es, err := g.ConnectingEdges(g.Node(0), g.Node(1))
+	c.Assert(err, check.IsNil)
+	labels := make(map[string]int)
+	for _, e := range es {
+		labels[e.Label()] = e.ID()
+	}
+	c.Check(labels["likes"], check.Equals, e0.ID())
+	c.Check(labels["follows"], check.Equals, e1.ID())
+	c.Check(labels[""], check.Equals, 0)
+
+	cl := g.Clone()
+	c.Check(cl.Edge(e0.ID()).Label(), check.Equals, "likes")
+	c.Check(cl.Edge(e1.ID()).Label(), check.Equals, "follows")
+}
+
+func (s *S) TestUndirectedComplement(c *check.C) {
+	g := path(c, 4)
+	g.ConnectByID(1, 2, 1, 0)
+	g.ConnectByID(3, 3, 1, 0)
+	cg := g.Complement()
+	c.Check(cg.Order(), check.Equals, 4)
+	c.Check(cg.Size(), check.Equals, 3)
+	var pairs [][2]int
+	for _, e := range cg.Edges() {
+		pairs = append(pairs, [2]int{e.Tail().ID(), e.Head().ID()})
+	}
+	c.Check(pairs, check.DeepEquals, [][2]int{{0, 2}, {0, 3}, {1, 3}})
+}
+
+func (s *S) TestUndirectedSimplify(c *check.C) {
+	g := weightedUndirected(c, []we{{0, 1, 1}, {1, 0, 2}, {0, 1, 4}, {1, 2, 1}, {2, 2, 1}, {2, 2, 1}})
+	sum := func(a, b Edge) Edge {
+		e := NewEdge()
+		e.SetWeight(a.Weight() + b.Weight())
+		return e
+	}
+
+	sg := g.Simplify(sum, false)
+	c.Check(sg.Order(), check.Equals, 3)
+	c.Check(sg.Size(), check.Equals, 2)
+	c.Check(sg.Edge(0).Weight(), check.Equals, 7.)
+	c.Check(sg.Edge(3).Weight(), check.Equals, 1.)
+	c.Check(sg.SelfLoops(), check.HasLen, 0)
+	c.Check(g.Size(), check.Equals, 6)
+
+	sg = g.Simplify(nil, true)
+	c.Check(sg.Size(), check.Equals, 3)
+	c.Check(sg.Edge(0).Weight(), check.Equals, 1.)
+	loops := sg.SelfLoops()
+	c.Assert(loops, check.HasLen, 1)
+	c.Check(loops[0].ID(), check.Equals, 4)
+}
+
+func (s *S) TestUndirectedDFSOrder(c *check.C) {
+	// A tree rooted at 0 with children 1 and 2, where 1 has children 3 and 4.
+	g := weightedUndirected(c, []we{{0, 1, 1}, {1, 3, 1}, {1, 4, 1}, {0, 2, 1}})
+	g.AddID(5)
+	d, f := g.DFSOrder(g.Node(0), all)
+	c.Check(d, check.DeepEquals, map[int]int{0: 0, 1: 1, 3: 2, 4: 4, 2: 7})
+	c.Check(f, check.DeepEquals, map[int]int{3: 3, 4: 5, 1: 6, 2: 8, 0: 9})
+
+	for u := range d {
+		for v := range d {
+			if u == v {
+				continue
+			}
+			nested := d[u] < d[v] && f[v] < f[u]
+			disjoint := f[u] < d[v] || f[v] < d[u]
+			c.Check(nested || disjoint || d[v] < d[u] && f[u] < f[v], check.Equals, true)
+		}
+	}
+}
+
+func (s *S) TestUndirectedEachEdge(c *check.C) {
+	g := undirected(c, uv)
+	var ids []int
+	g.EachEdge(func(e Edge) bool {
+		ids = append(ids, e.ID())
+		return true
+	})
+	var want []int
+	for _, e := range g.Edges() {
+		want = append(want, e.ID())
+	}
+	c.Check(ids, check.DeepEquals, want)
+
+	n := 0
+	g.EachEdge(func(e Edge) bool {
+		n++
+		return n < 2
+	})
+	c.Check(n, check.Equals, 2)
+}
+
+func (s *S) TestNodeEachNeighbor(c *check.C) {
+	g := undirected(c, uv)
+	f := func(_ Edge) bool { return true }
+	for _, n := range g.Nodes() {
+		var nb []Node
+		n.EachNeighbor(f, func(a Node) bool {
+			nb = append(nb, a)
+			return true
+		})
+		c.Check(nb, check.DeepEquals, n.Neighbors(f))
+	}
+}
+
+func (s *S) TestNodeUniqueNeighbors(c *check.C) {
+	g := undirected(c, []e{{0, 1}, {1, 0}, {0, 1}})
+	c.Check(g.Node(0).Neighbors(all), check.HasLen, 3)
+	nb := g.Node(0).UniqueNeighbors(all)
+	c.Assert(nb, check.HasLen, 1)
+	c.Check(nb[0], check.Equals, g.Node(1))
+
+	_, err := g.AddID(2)
+	c.Assert(err, check.IsNil)
+	for _, uv := range [][2]int{{1, 1}, {1, 1}, {1, 2}} {
+		_, err = g.ConnectByID(uv[0], uv[1], 1, 0)
+		c.Assert(err, check.IsNil)
+	}
+	var ids []int
+	for _, n := range g.Node(1).UniqueNeighbors(all) {
+		ids = append(ids, n.ID())
+	}
+	c.Check(ids, check.DeepEquals, []int{0, 1, 2})
+}
+
+func BenchmarkUndirectedNeighbors(b *testing.B) {
+	g := createGraph(testG[0])
+	f := func(_ Edge) bool { return true }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, e := range g.Edges() {
+			for _, n := range e.Head().Neighbors(f) {
+				_ = n
+			}
+		}
+	}
+}
+
+func BenchmarkUndirectedEachNeighbor(b *testing.B) {
+	g := createGraph(testG[0])
+	f := func(_ Edge) bool { return true }
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.EachEdge(func(e Edge) bool {
+			e.Head().EachNeighbor(f, func(n Node) bool { return true })
+			return true
+		})
+	}
+}
+
+func benchmarkSparse(n, m int) *Undirected {
+	rnd := rand.New(rand.NewSource(1))
+	pairs := make([][2]int, m)
+	for i := range pairs {
+		pairs[i] = [2]int{rnd.Intn(n), rnd.Intn(n)}
+	}
+	g := NewUndirected()
+	for i := 0; i < n; i++ {
+		g.AddID(i)
+	}
+	g.AddEdges(pairs, nil)
+	return g
+}
+
+func BenchmarkConnectedComponents(b *testing.B) {
+	g := benchmarkSparse(1e5, 1e5)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.ConnectedComponents(all)
+	}
+}
+
+func BenchmarkConnectedComponentsPar(b *testing.B) {
+	g := benchmarkSparse(1e5, 1e5)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		g.ConnectedComponentsPar(MaxProcs)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	"math/rand"
+)
+
+// RandomWalk returns the nodes visited by a random walk of up to steps steps from the node start,
+// beginning with start. At each step an edge incident on the current node is chosen with probability
+// proportional to its weight, and the walk moves to the node at its other end. Edge weights must not
+// be negative. If the walk reaches a node with no edges of positive weight, it ends early. Random
+// numbers are taken from src. If start does not exist in the graph, nil is returned.
+func (g *Undirected) RandomWalk(start Node, steps int, src rand.Source) []Node {
+	if ok, _ := g.Has(start); !ok {
+		return nil
+	}
+	rnd := rand.New(src)
+	all := func(_ Edge) bool { return true }
+
+	type choice struct {
+		hops []*Hop
+		sel  Selector
+	}
+	choices := make(map[int]*choice)
+
+	walk := []Node{start}
+	for n := start; len(walk) <= steps; {
+		ch, ok := choices[n.ID()]
+		if !ok {
+			ch = &choice{hops: n.Hops(all)}
+			ch.sel = make(Selector, len(ch.hops))
+			for i, h := range ch.hops {
+				ch.sel[i] = WeightedItem{Index: i, Weight: h.Edge.Weight()}
+			}
+			ch.sel.Init()
+			choices[n.ID()] = ch
+		}
+		if ch.sel.Remaining() <= 0 {
+			break
+		}
+		n = ch.hops[ch.sel[ch.sel.choose(rnd)-1].Index].Node
+		walk = append(walk, n)
+	}
+
+	return walk
+}
+
+// SampleEdges returns k distinct edges of the graph sampled without replacement, each successive edge
+// being chosen from those remaining with probability proportional to its weight. Edge weights must not
+// be negative. If k is at least Size, all the edges of the graph are returned, and if fewer than k
+// edges have positive weight, only those edges are returned. Random numbers are taken from src.
+func (g *Undirected) SampleEdges(k int, src rand.Source) []Edge {
+	if k >= len(g.compEdges) {
+		return append([]Edge(nil), g.compEdges...)
+	}
+	rnd := rand.New(src)
+
+	sel := make(Selector, len(g.compEdges))
+	for i, e := range g.compEdges {
+		sel[i] = WeightedItem{Index: i, Weight: e.Weight()}
+	}
+	sel.Init()
+
+	var sample []Edge
+	for len(sample) < k {
+		i, err := sel.SelectWith(rnd)
+		if err != nil {
+			break
+		}
+		sample = append(sample, g.compEdges[i])
+	}
+
+	return sample
+}
@@ -0,0 +1,68 @@
+// Copyright ©2012 Dan Kortschak <dan.kortschak@adelaide.edu.au>
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package graph
+
+import (
+	check "launchpad.net/gocheck"
+	"math/rand"
+)
+
+// Tests
+func (s *S) TestRandomWalk(c *check.C) {
+	g := weightedUndirected(c, []we{{0, 1, 1}, {1, 2, 2}, {2, 0, 4}})
+	walk := g.RandomWalk(g.Node(0), 1000, rand.NewSource(1))
+	c.Check(walk, check.HasLen, 1001)
+	c.Check(walk[0], check.Equals, g.Node(0))
+	visits := make(map[int]int)
+	for i, n := range walk {
+		ok, _ := g.Has(n)
+		c.Check(ok, check.Equals, true)
+		if i > 0 {
+			c.Check(n, check.Not(check.Equals), walk[i-1])
+		}
+		visits[n.ID()]++
+	}
+	// The stationary distribution is proportional to the weighted degree of each node.
+	c.Check(visits[2] > visits[0] && visits[0] > visits[1], check.Equals, true, check.Commentf("%v", visits))
+
+	g.AddID(3)
+	g.ConnectByID(2, 3, 0, 0)
+	c.Check(g.RandomWalk(g.Node(3), 10, rand.NewSource(1)), check.DeepEquals, []Node{g.Node(3)})
+	c.Check(g.RandomWalk(g.Node(0), 0, rand.NewSource(1)), check.DeepEquals, []Node{g.Node(0)})
+}
+
+func (s *S) TestSampleEdges(c *check.C) {
+	g := weightedUndirected(c, []we{{0, 1, 1}, {1, 2, 2}, {2, 3, 4}, {3, 0, 8}, {0, 2, 0}})
+	rnd := rand.New(rand.NewSource(1))
+	counts := make(map[int]int)
+	for i := 0; i < 2000; i++ {
+		sample := g.SampleEdges(2, rnd)
+		c.Assert(sample, check.HasLen, 2)
+		c.Check(sample[0], check.Not(check.Equals), sample[1])
+		for _, e := range sample {
+			counts[e.ID()]++
+		}
+	}
+	c.Check(counts[4], check.Equals, 0)
+	c.Check(counts[3] > counts[2] && counts[2] > counts[1] && counts[1] > counts[0], check.Equals, true, check.Commentf("%v", counts))
+
+	c.Check(g.SampleEdges(5, rnd), check.DeepEquals, g.Edges())
+	c.Check(g.SampleEdges(10, rnd), check.HasLen, g.Size())
+	c.Check(g.SampleEdges(0, rnd), check.HasLen, 0)
+	// Only four edges have positive weight.
+	g.ConnectByID(1, 3, 0, 0)
+	c.Check(g.SampleEdges(5, rnd), check.HasLen, 4)
+}
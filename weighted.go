@@ -18,6 +18,7 @@ package graph
 import (
 	"fmt"
 	"math/rand"
+	"sync"
 )
 
 // SelectorEmpty is returned when an attempt is made to select an item from a Selector with
@@ -27,16 +28,45 @@ var SelectorEmpty = fmt.Errorf("graph: selector empty")
 // A WeightedItem is a type that can be be selected from a population with a defined probability
 // specified by the field Weight. Index is used as an index to the actual item in another slice.
 type WeightedItem struct {
-	Index         int
-	Weight, total float64
+	Index                  int
+	Weight, total, initial float64
 }
 
 // A Selector is a collection of weighted items that can be selected with weighted probabilities
-// without replacement.
+// without replacement. A Selector is not safe for concurrent use; SyncSelector provides a wrapper
+// that is.
 type Selector []WeightedItem
 
 // Init must be called on a Selector before it is selected from. Init is idempotent.
 func (s Selector) Init() {
+	for i := range s {
+		s[i].initial = s[i].Weight
+	}
+	s.build()
+}
+
+// Append adds item to the Selector, updating the total tree so that the item is immediately available
+// for selection without a further call to Init. The Selector must have been initialised by Init or
+// built only by Append. The weight of item is recorded as its initial weight for Reset.
+func (s *Selector) Append(item WeightedItem) {
+	item.total, item.initial = item.Weight, item.Weight
+	*s = append(*s, item)
+	for i := len(*s) >> 1; i > 0; i >>= 1 {
+		(*s)[i-1].total += item.Weight
+	}
+}
+
+// Reset restores the weight of each item in the Selector to the value it held when Init was last
+// called, making items removed by Select or altered by Weight available for selection again.
+func (s Selector) Reset() {
+	for i := range s {
+		s[i].Weight = s[i].initial
+	}
+	s.build()
+}
+
+// build constructs the total tree from the current item weights.
+func (s Selector) build() {
 	for i := range s {
 		s[i].total = s[i].Weight
 	}
@@ -49,10 +79,68 @@ func (s Selector) Init() {
 // Select returns the value of the Index field of the chosen WeightedItem and the item is weighted 
 // zero to prevent further selection.
 func (s Selector) Select() (int, error) {
+	return s.SelectWith(nil)
+}
+
+// SelectWith behaves as Select, but uses rnd as the source of random numbers. If rnd is nil, the
+// global math/rand source is used.
+func (s Selector) SelectWith(rnd *rand.Rand) (int, error) {
 	if s[0].total == 0 {
 		return -1, SelectorEmpty
 	}
-	r, i := s[0].total*rand.Float64(), 1
+	i := s.choose(rnd)
+
+	w, index := s[i-1].Weight, s[i-1].Index
+
+	s[i-1].Weight = 0
+	for i > 0 {
+		s[i-1].total -= w
+		i >>= 1
+	}
+
+	return index, nil
+}
+
+// SelectN returns the values of the Index fields of up to n WeightedItems chosen without replacement,
+// in the order they were selected. If the Selector is exhausted before n items have been chosen, the
+// items selected so far are returned with SelectorEmpty. If n is negative, an error is returned.
+// SelectN is a convenience equivalent to n calls to Select and costs the same.
+func (s Selector) SelectN(n int) ([]int, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("graph: negative selection count %d", n)
+	}
+	idx := make([]int, 0, n)
+	for len(idx) < n {
+		i, err := s.SelectWith(nil)
+		if err != nil {
+			return idx, err
+		}
+		idx = append(idx, i)
+	}
+
+	return idx, nil
+}
+
+// SelectReplace returns the value of the Index field of a WeightedItem chosen with probability
+// proportional to its weight, without altering the weight of the item, so repeated selections are
+// made from the same distribution. As with Select, Init must be called before SelectReplace is used.
+func (s Selector) SelectReplace() (int, error) {
+	if s[0].total == 0 {
+		return -1, SelectorEmpty
+	}
+	return s[s.choose(nil)-1].Index, nil
+}
+
+// choose returns the 1-based position in s of an item chosen with probability proportional to its
+// weight, using rnd as the source of random numbers or the global math/rand source if rnd is nil.
+func (s Selector) choose(rnd *rand.Rand) int {
+	var f float64
+	if rnd == nil {
+		f = rand.Float64()
+	} else {
+		f = rnd.Float64()
+	}
+	r, i := s[0].total*f, 1
 
 	for {
 		if r -= s[i-1].Weight; r <= 0 {
@@ -68,15 +156,21 @@ func (s Selector) Select() (int, error) {
 		}
 	}
 
-	w, index := s[i-1].Weight, s[i-1].Index
+	return i
+}
 
-	s[i-1].Weight = 0
-	for i > 0 {
-		s[i-1].total -= w
-		i >>= 1
+// Remaining returns the total weight of the items remaining available for selection in the Selector.
+func (s Selector) Remaining() float64 {
+	if len(s) == 0 {
+		return 0
 	}
+	return s[0].total
+}
 
-	return index, nil
+// WeightOf returns the current weight of item i in the Selector. Items that have been selected have
+// a weight of zero.
+func (s Selector) WeightOf(i int) float64 {
+	return s[i].Weight
 }
 
 // Weight alters the weight of item i in the Selector.
@@ -88,3 +182,42 @@ func (s Selector) Weight(i int, w float64) {
 		i >>= 1
 	}
 }
+
+// A SyncSelector is a Selector that is safe for concurrent use by multiple goroutines.
+type SyncSelector struct {
+	mu sync.Mutex
+	s  Selector
+}
+
+// NewSyncSelector returns a SyncSelector wrapping s. The caller should not use s directly after the
+// call.
+func NewSyncSelector(s Selector) *SyncSelector {
+	return &SyncSelector{s: s}
+}
+
+// Init calls Init on the underlying Selector.
+func (ss *SyncSelector) Init() {
+	ss.mu.Lock()
+	ss.s.Init()
+	ss.mu.Unlock()
+}
+
+// Select behaves as Selector.Select.
+func (ss *SyncSelector) Select() (int, error) {
+	return ss.SelectWith(nil)
+}
+
+// SelectWith behaves as Selector.SelectWith. If rnd is not nil, the global math/rand source is not
+// used.
+func (ss *SyncSelector) SelectWith(rnd *rand.Rand) (int, error) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	return ss.s.SelectWith(rnd)
+}
+
+// Weight behaves as Selector.Weight.
+func (ss *SyncSelector) Weight(i int, w float64) {
+	ss.mu.Lock()
+	ss.s.Weight(i, w)
+	ss.mu.Unlock()
+}
@@ -18,6 +18,8 @@ package graph
 import (
 	check "launchpad.net/gocheck"
 	"math/rand"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -39,40 +41,40 @@ var (
 		{Index: 10, Weight: exp[9]},
 	}
 	tot = Selector{
-		{Index: 1, Weight: exp[0], total: exp[0] + exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9] + exp[2] + exp[5] + exp[6]},
-		{Index: 2, Weight: exp[1], total: exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9]},
-		{Index: 3, Weight: exp[2], total: exp[2] + exp[5] + exp[6]},
-		{Index: 4, Weight: exp[3], total: exp[3] + exp[7] + exp[8]},
-		{Index: 5, Weight: exp[4], total: exp[4] + exp[9]},
-		{Index: 6, Weight: exp[5], total: exp[5]},
-		{Index: 7, Weight: exp[6], total: exp[6]},
-		{Index: 8, Weight: exp[7], total: exp[7]},
-		{Index: 9, Weight: exp[8], total: exp[8]},
-		{Index: 10, Weight: exp[9], total: exp[9]},
+		{Index: 1, Weight: exp[0], total: exp[0] + exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9] + exp[2] + exp[5] + exp[6], initial: exp[0]},
+		{Index: 2, Weight: exp[1], total: exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9], initial: exp[1]},
+		{Index: 3, Weight: exp[2], total: exp[2] + exp[5] + exp[6], initial: exp[2]},
+		{Index: 4, Weight: exp[3], total: exp[3] + exp[7] + exp[8], initial: exp[3]},
+		{Index: 5, Weight: exp[4], total: exp[4] + exp[9], initial: exp[4]},
+		{Index: 6, Weight: exp[5], total: exp[5], initial: exp[5]},
+		{Index: 7, Weight: exp[6], total: exp[6], initial: exp[6]},
+		{Index: 8, Weight: exp[7], total: exp[7], initial: exp[7]},
+		{Index: 9, Weight: exp[8], total: exp[8], initial: exp[8]},
+		{Index: 10, Weight: exp[9], total: exp[9], initial: exp[9]},
 	}
 	dnw = Selector{
-		{Index: 1, Weight: exp[0], total: exp[0] + exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9] + exp[2] + exp[5]},
-		{Index: 2, Weight: exp[1], total: exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9]},
-		{Index: 3, Weight: exp[2], total: exp[2] + exp[5]},
-		{Index: 4, Weight: exp[3], total: exp[3] + exp[7] + exp[8]},
-		{Index: 5, Weight: exp[4], total: exp[4] + exp[9]},
-		{Index: 6, Weight: exp[5], total: exp[5]},
-		{Index: 7, Weight: 0, total: 0},
-		{Index: 8, Weight: exp[7], total: exp[7]},
-		{Index: 9, Weight: exp[8], total: exp[8]},
-		{Index: 10, Weight: exp[9], total: exp[9]},
+		{Index: 1, Weight: exp[0], total: exp[0] + exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9] + exp[2] + exp[5], initial: exp[0]},
+		{Index: 2, Weight: exp[1], total: exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9], initial: exp[1]},
+		{Index: 3, Weight: exp[2], total: exp[2] + exp[5], initial: exp[2]},
+		{Index: 4, Weight: exp[3], total: exp[3] + exp[7] + exp[8], initial: exp[3]},
+		{Index: 5, Weight: exp[4], total: exp[4] + exp[9], initial: exp[4]},
+		{Index: 6, Weight: exp[5], total: exp[5], initial: exp[5]},
+		{Index: 7, Weight: 0, total: 0, initial: exp[6]},
+		{Index: 8, Weight: exp[7], total: exp[7], initial: exp[7]},
+		{Index: 9, Weight: exp[8], total: exp[8], initial: exp[8]},
+		{Index: 10, Weight: exp[9], total: exp[9], initial: exp[9]},
 	}
 	upw = Selector{
-		{Index: 1, Weight: exp[0], total: exp[0] + exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9] + exp[2] + exp[5] + exp[9]*2},
-		{Index: 2, Weight: exp[1], total: exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9]},
-		{Index: 3, Weight: exp[2], total: exp[2] + exp[5] + exp[9]*2},
-		{Index: 4, Weight: exp[3], total: exp[3] + exp[7] + exp[8]},
-		{Index: 5, Weight: exp[4], total: exp[4] + exp[9]},
-		{Index: 6, Weight: exp[5], total: exp[5]},
-		{Index: 7, Weight: exp[9] * 2, total: exp[9] * 2},
-		{Index: 8, Weight: exp[7], total: exp[7]},
-		{Index: 9, Weight: exp[8], total: exp[8]},
-		{Index: 10, Weight: exp[9], total: exp[9]},
+		{Index: 1, Weight: exp[0], total: exp[0] + exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9] + exp[2] + exp[5] + exp[9]*2, initial: exp[0]},
+		{Index: 2, Weight: exp[1], total: exp[1] + exp[3] + exp[4] + exp[7] + exp[8] + exp[9], initial: exp[1]},
+		{Index: 3, Weight: exp[2], total: exp[2] + exp[5] + exp[9]*2, initial: exp[2]},
+		{Index: 4, Weight: exp[3], total: exp[3] + exp[7] + exp[8], initial: exp[3]},
+		{Index: 5, Weight: exp[4], total: exp[4] + exp[9], initial: exp[4]},
+		{Index: 6, Weight: exp[5], total: exp[5], initial: exp[5]},
+		{Index: 7, Weight: exp[9] * 2, total: exp[9] * 2, initial: exp[6]},
+		{Index: 8, Weight: exp[7], total: exp[7], initial: exp[7]},
+		{Index: 9, Weight: exp[8], total: exp[8], initial: exp[8]},
+		{Index: 10, Weight: exp[9], total: exp[9], initial: exp[9]},
 	}
 
 	obt = []float64{973, 1937, 3898, 7897, 15769, 31284, 62176, 125408, 250295, 500363}
@@ -223,6 +225,201 @@ func (s *S) TestWeightIncrease(c *check.C) {
 	c.Check(f[6] > f[9], check.Equals, true)
 }
 
+func (s *S) TestSelectReplace(c *check.C) {
+	rand.Seed(0)
+	f := make([]float64, len(sel))
+	ts := make(Selector, len(sel))
+
+	copy(ts, sel)
+	ts.Init()
+	for i := 0; i < 1e6; i++ {
+		item, err := ts.SelectReplace()
+		if err != nil {
+			c.Fatal(err)
+		}
+		f[item-1]++
+	}
+
+	// The tree must not be altered by selection with replacement.
+	c.Check(ts, check.DeepEquals, tot)
+
+	fsum, exsum := 0., 0.
+	for i := range f {
+		fsum += f[i]
+		exsum += sel[i].Weight
+	}
+	ex := make([]float64, len(sel))
+	for i := range ex {
+		ex[i] = sel[i].Weight * fsum / exsum
+	}
+
+	// Check that this is within statistical expectations - we know this is true for this seed.
+	X := chi2(f, ex)
+	c.Logf("H₀: d(Sample) = d(Expect), H₁: d(S) ≠ d(Expect). df = %d, p = 0.05, X² threshold = %.2f, X² = %f", len(f)-1, sigChi2, X)
+	c.Check(X < sigChi2, check.Equals, true)
+}
+
+func (s *S) TestSelectorReset(c *check.C) {
+	rand.Seed(0)
+	ts := make(Selector, len(sel))
+
+	copy(ts, sel)
+	ts.Init()
+	var first []int
+	for {
+		item, err := ts.Select()
+		if err != nil {
+			c.Check(err, check.Equals, SelectorEmpty)
+			break
+		}
+		first = append(first, item)
+	}
+	c.Check(len(first), check.Equals, len(sel))
+
+	ts.Reset()
+	c.Check(ts, check.DeepEquals, tot)
+
+	var second []int
+	for {
+		item, err := ts.Select()
+		if err != nil {
+			c.Check(err, check.Equals, SelectorEmpty)
+			break
+		}
+		second = append(second, item)
+	}
+	sort.Ints(first)
+	sort.Ints(second)
+	c.Check(second, check.DeepEquals, first)
+}
+
+func (s *S) TestSelectN(c *check.C) {
+	rand.Seed(0)
+	ts := make(Selector, len(sel))
+
+	copy(ts, sel)
+	ts.Init()
+	idx, err := ts.SelectN(len(sel) / 2)
+	c.Check(err, check.Equals, nil)
+	c.Check(len(idx), check.Equals, len(sel)/2)
+
+	ts.Reset()
+	idx, err = ts.SelectN(len(sel) + 1)
+	c.Check(err, check.Equals, SelectorEmpty)
+	sort.Ints(idx)
+	all := make([]int, len(sel))
+	for i, it := range sel {
+		all[i] = it.Index
+	}
+	c.Check(idx, check.DeepEquals, all)
+
+	ts.Reset()
+	idx, err = ts.SelectN(-1)
+	c.Check(err, check.ErrorMatches, "graph: negative selection count -1")
+	c.Check(idx, check.IsNil)
+}
+
+func (s *S) TestSyncSelector(c *check.C) {
+	const n = 1000
+	ts := make(Selector, n)
+	for i := range ts {
+		ts[i] = WeightedItem{Index: i, Weight: 1}
+	}
+	ss := NewSyncSelector(ts)
+	ss.Init()
+
+	var (
+		wg   sync.WaitGroup
+		seen = make([][]int, 4)
+	)
+	for g := range seen {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(int64(g)))
+			for {
+				i, err := ss.SelectWith(rnd)
+				if err != nil {
+					return
+				}
+				seen[g] = append(seen[g], i)
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	var all []int
+	for _, s := range seen {
+		all = append(all, s...)
+	}
+	sort.Ints(all)
+	c.Assert(len(all), check.Equals, n)
+	for i, v := range all {
+		c.Check(v, check.Equals, i)
+	}
+}
+
+func (s *S) TestSelectorRemaining(c *check.C) {
+	rand.Seed(0)
+	ts := make(Selector, len(sel))
+
+	copy(ts, sel)
+	ts.Init()
+	var sum float64
+	for _, it := range sel {
+		sum += it.Weight
+	}
+	c.Check(ts.Remaining(), check.Equals, sum)
+
+	for ts.Remaining() > 0 {
+		r := ts.Remaining()
+		item, err := ts.Select()
+		c.Assert(err, check.Equals, nil)
+		c.Check(ts.WeightOf(item-1), check.Equals, 0.)
+		c.Check(r-ts.Remaining(), check.Equals, sel[item-1].Weight)
+	}
+	_, err := ts.Select()
+	c.Check(err, check.Equals, SelectorEmpty)
+}
+
+func (s *S) TestSelectorAppend(c *check.C) {
+	rand.Seed(0)
+	ts := make(Selector, 5)
+	copy(ts, sel)
+	ts.Init()
+	for _, it := range sel[5:] {
+		ts.Append(it)
+	}
+	c.Check(ts, check.DeepEquals, tot)
+
+	var es Selector
+	for _, it := range sel {
+		es.Append(it)
+	}
+	c.Check(es, check.DeepEquals, tot)
+
+	f := make([]float64, len(sel))
+	for i := 0; i < 1e6; i++ {
+		item, err := ts.SelectReplace()
+		if err != nil {
+			c.Fatal(err)
+		}
+		f[item-1]++
+	}
+	fsum, exsum := 0., 0.
+	for i := range f {
+		fsum += f[i]
+		exsum += sel[i].Weight
+	}
+	ex := make([]float64, len(sel))
+	for i := range ex {
+		ex[i] = sel[i].Weight * fsum / exsum
+	}
+	X := chi2(f, ex)
+	c.Logf("H₀: d(Sample) = d(Expect), H₁: d(S) ≠ d(Expect). df = %d, p = 0.05, X² threshold = %.2f, X² = %f", len(f)-1, sigChi2, X)
+	c.Check(X < sigChi2, check.Equals, true)
+}
+
 func chi2(ob, ex []float64) (sum float64) {
 	for i := range ob {
 		x := ob[i] - ex[i]